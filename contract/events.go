@@ -42,7 +42,8 @@ func emitEvent(eventType string, kv ...string) {
 //
 
 // EmitGameCreated announces a new lobby was created.
-func EmitGameCreated(id uint64, by string, betAmount *uint64, betAsset *sdk.Asset, gameType uint8, firstMoveCost *uint64, name string, ts uint64) {
+// Rule options are passed as extra key/value pairs and appended as-is.
+func EmitGameCreated(id uint64, by string, betAmount *uint64, betAsset *sdk.Asset, gameType uint8, firstMoveCost *uint64, name string, ts uint64, extra ...string) {
 	ba := uint64(0)
 	fmc := uint64(0)
 	aa := ""
@@ -53,7 +54,7 @@ func EmitGameCreated(id uint64, by string, betAmount *uint64, betAsset *sdk.Asse
 	if firstMoveCost != nil {
 		fmc = *firstMoveCost
 	}
-	emitEvent("c",
+	kv := []string{
 		"id", UInt64ToString(id),
 		"by", by,
 		"am", UInt64ToString(ba),
@@ -62,26 +63,67 @@ func EmitGameCreated(id uint64, by string, betAmount *uint64, betAsset *sdk.Asse
 		"fmc", UInt64ToString(uint64(fmc)),
 		"n", name,
 		"ts", UInt64ToString(ts),
-	)
+	}
+	emitEvent("c", append(kv, extra...)...)
 }
 
 // EmitGameJoined signals an opponent joined a game.
-func EmitGameJoined(id uint64, joiner string, fmp bool, ts uint64) {
-	emitEvent("j",
+// Values decided at join (seeded obstacles) are appended as extra pairs.
+func EmitGameJoined(id uint64, joiner string, fmp bool, ts uint64, extra ...string) {
+	kv := []string{
 		"id", UInt64ToString(id),
 		"by", joiner,
 		"fmp", strconv.FormatBool(fmp),
 		"ts", UInt64ToString(ts),
-	)
+	}
+	emitEvent("j", append(kv, extra...)...)
 }
 
 // EmitGameMoveMade records a move coordinate as a single pos index (row*cols+col).
-func EmitGameMoveMade(id uint64, by string, pos uint8, ts uint64) {
+// Cells captured by the move (pente) are added as a comma separated "cap" list.
+// Boards with more than 256 cells mark the event with "v=2", telling indexers
+// that cell indices are 16-bit; small boards keep the original format.
+// A symbol other than Empty (Order and Chaos) is reported as "sym".
+func EmitGameMoveMade(id uint64, by string, pos uint16, ts uint64, wide bool, symbol Cell, captured ...uint16) {
+	kv := []string{
+		"id", UInt64ToString(id),
+		"by", by,
+		"cell", UInt64ToString(uint64(pos)),
+		"ts", UInt64ToString(ts),
+	}
+	if symbol != Empty {
+		kv = append(kv, "sym", UInt64ToString(uint64(symbol)))
+	}
+	if len(captured) > 0 {
+		kv = append(kv, "cap", string(appendU16List(nil, captured)))
+	}
+	if wide {
+		kv = append(kv, "v", "2")
+	}
+	emitEvent("m", kv...)
+}
+
+// EmitGamePopMade records a PopOut pop as an "m" event with pop=1.
+// Cell is the bottom-row cell the disc was taken from.
+func EmitGamePopMade(id uint64, by string, pos uint16, ts uint64) {
 	emitEvent("m",
 		"id", UInt64ToString(id),
 		"by", by,
 		"cell", UInt64ToString(uint64(pos)),
 		"ts", UInt64ToString(ts),
+		"pop", "1",
+	)
+}
+
+// EmitGamePieceMoved records a movement-phase move as an "m" event.
+// Cell is the target point, "from" the point the piece left.
+func EmitGamePieceMoved(id uint64, by string, from, to uint16, ts uint64) {
+	emitEvent("m",
+		"id", UInt64ToString(id),
+		"by", by,
+		"cell", UInt64ToString(uint64(to)),
+		"ts", UInt64ToString(ts),
+		"from", UInt64ToString(uint64(from)),
 	)
 }
 
@@ -94,6 +136,29 @@ func EmitGameWon(id uint64, winner string, ts uint64) {
 	)
 }
 
+// EmitGameCancelled announces a game that ended without a result and
+// refunded its stakes. Reason is "cancelled" (lobby closed by its
+// creator), "aborted" (joined game ended before the first stone),
+// "expired" (join deadline passed) or "declined" (every invitee declined).
+func EmitGameCancelled(id uint64, reason string, ts uint64) {
+	emitEvent("x",
+		"id", UInt64ToString(id),
+		"reason", reason,
+		"ts", UInt64ToString(ts),
+	)
+}
+
+// EmitGameDeclined logs an invited account refusing a lobby. closed=1
+// tells indexers that nobody invited is left and the stake went back.
+func EmitGameDeclined(id uint64, by string, closed bool, ts uint64) {
+	emitEvent("n",
+		"id", UInt64ToString(id),
+		"by", by,
+		"closed", UInt64ToString(uint64(boolByte(closed))),
+		"ts", UInt64ToString(ts),
+	)
+}
+
 // EmitGameResigned logs a resignation, so UIs can highlight that reason.
 func EmitGameResigned(id uint64, resignedAddress string, ts uint64) {
 	emitEvent("r",
@@ -120,11 +185,77 @@ func EmitGameDraw(id uint64, ts uint64) {
 	)
 }
 
+// EmitDrawOffered logs a draw offer by one player.
+func EmitDrawOffered(id uint64, by string, ts uint64) {
+	emitEvent("o",
+		"id", UInt64ToString(id),
+		"by", by,
+		"ts", UInt64ToString(ts),
+	)
+}
+
+// EmitDrawDeclined logs the opponent turning a draw offer down. An
+// accepted offer ends with a regular "d" event instead.
+func EmitDrawDeclined(id uint64, by string, ts uint64) {
+	emitEvent("e",
+		"id", UInt64ToString(id),
+		"by", by,
+		"ts", UInt64ToString(ts),
+	)
+}
+
+// EmitPieSwap records a pie rule swap: by took over X's first move
+// and now plays X, the former X player continues as O.
+func EmitPieSwap(id uint64, by string, ts uint64) {
+	emitEvent("p",
+		"id", UInt64ToString(id),
+		"by", by,
+		"ts", UInt64ToString(ts),
+	)
+}
+
+// EmitAuctionBid logs a first-move auction action: op is "bid" or
+// "reveal" with the amount, or "commit" for a sealed bid (no amount).
+func EmitAuctionBid(id uint64, by string, op string, amount string, ts uint64) {
+	emitEvent("b",
+		"id", UInt64ToString(id),
+		"by", by,
+		"op", op,
+		"amt", amount,
+		"ts", UInt64ToString(ts),
+	)
+}
+
+// EmitAuctionSettled announces the auction result: x plays first and
+// paid price to the other player (0 when seats stayed unchanged).
+func EmitAuctionSettled(id uint64, x string, price uint64, ts uint64) {
+	emitEvent("f",
+		"id", UInt64ToString(id),
+		"x", x,
+		"price", UInt64ToString(price),
+		"ts", UInt64ToString(ts),
+	)
+}
+
+// EmitCoinFlipReveal logs a revealed coin flip secret. Once both are
+// known, x names the player who got X; it stays empty before that.
+func EmitCoinFlipReveal(id uint64, by string, secret string, x string, ts uint64) {
+	emitEvent("v",
+		"id", UInt64ToString(id),
+		"by", by,
+		"secret", secret,
+		"x", x,
+		"ts", UInt64ToString(ts),
+	)
+}
+
 //
 // Swap2 (Gomoku special opening rule) events
 //
 
-func EmitSwapEvent(id uint64, by string, op string, cell *uint8, color *uint8, choice *string, ts uint64) {
+// EmitSwapEvent logs one opening sub-move. Cell is a row*cols+col index
+// like in the "m" event; swap boards are 15x15 so no version marker is needed.
+func EmitSwapEvent(id uint64, by string, op string, cell *uint16, color *uint8, choice *string, ts uint64) {
 	ce := ""
 	co := ""
 	ch := ""
@@ -7,26 +7,31 @@ import (
 
 // CreateGame starts a fresh match and stores its basic meta.
 // The full board state is not saved yet, since no moves exist.
-// Caller must pass "type|name|fmc" where fmc is optional.
+// Caller must pass "type|name|fmc" where fmc is optional, optionally
+// followed by "key=value" rule options (e.g. "fb=lose" for renju).
 // Returns the new game ID as a string pointer.
 //
 //go:wasmexport g_create
 func CreateGame(payload *string) *string {
-	gt, name, fmc := parseCreateArgs(payload)
+	gt, name, fmc, opts := parseCreateArgs(payload)
 
 	sender := *sdk.GetEnvKey("msg.sender")
 	id := getGameCount()
 	ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
 
 	g := initNewGame(gt, name, sender, ts, id, fmc)
+	applyCreateOptions(g, opts)
 	applyOptionalBetOnCreate(g)
 	if fmc > 0 {
 		require(g.GameAsset != nil, "first-move purchase only available in betting games")
 	}
+	validateAuction(g)
+	validateCoinFlip(g)
+	validateClock(g)
 
 	saveMetaBinary(g) // no state write yet
 	setGameCount(id + 1)
-	EmitGameCreated(g.ID, sender, g.GameBetAmount, g.GameAsset, uint8(g.Type), g.FirstMoveCosts, g.Name, ts)
+	EmitGameCreated(g.ID, sender, g.GameBetAmount, g.GameAsset, uint8(g.Type), g.FirstMoveCosts, g.Name, ts, createEventOptions(g)...)
 
 	ret := UInt64ToString(g.ID)
 	return &ret
@@ -34,32 +39,71 @@ func CreateGame(payload *string) *string {
 
 // JoinGame lets a second player enter a waiting match.
 // Handles optional buy-first-move logic and bet escrow.
-// Becomes active once joined; swap2 pre-phase init fires for Gomoku.
+// Becomes active once joined; the opening (swap2 or the chosen rule) starts for Gomoku.
+// Coin flip games add the joiner's commitment, locked lobbies the
+// password: "id|commitment|password", each only when the game uses it.
 //
 //go:wasmexport g_join
 func JoinGame(payload *string) *string {
 	in := *payload
 	gameId := parseU64Fast(nextField(&in))
-	require(in == "", "too many arguments")
 
 	joiner := *sdk.GetEnvKey("msg.sender")
 	g := loadGame(gameId)
 
+	// optional fields, in this order: coin flip commitment, lobby password
+	var commit, secret string
+	if g.CoinFlip != nil {
+		commit = nextField(&in)
+	}
+	if g.JoinHash != nil {
+		// the password is the last field, so a '|' in it is caught by requireJoinSecret
+		secret, in = in, ""
+	}
+	require(in == "", "too many arguments")
+
 	require(g.Status == WaitingForPlayer, "cannot join: state is "+UInt64ToString(uint64(g.Status)))
 	require(joiner != g.Creator, "creator cannot join")
+	require(canJoin(g, joiner), "not invited")
+	ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
+	require(!lobbyExpired(g, ts), "lobby expired")
+	if g.JoinHash != nil {
+		requireJoinSecret(g, secret)
+	}
 
 	g.Opponent = &joiner
 
 	wants, base, fm, token := wantsFirstMoveAndAssertFunding(g)
 	settleJoinerFundsAndRoles(g, joiner, wants, base, fm, token)
 
+	// random obstacles are drawn from the join tx, so the creator can't pick them
+	var extra []string
+	if g.BlockCount > 0 {
+		seedBlockedCells(g, *sdk.GetEnvKey("tx.id"), *sdk.GetEnvKey("block.id"))
+		extra = append(extra, "blocked", string(appendU16List(nil, g.Blocked)))
+	}
+
 	g.Status = InProgress
+	g.JoinedAt = ts
+	g.LastMoveAt = ts
 	saveMetaBinary(g)
 	saveStateBinary(g)
 
-	initSwap2IfGomokuBinary(g)
-	ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
-	EmitGameJoined(g.ID, joiner, wants, ts)
+	if g.Auction != auctionNone {
+		startAuction(g, ts)
+	}
+	if g.CoinFlip != nil {
+		require(commit != "", "coinflip commitment missing")
+		startCoinFlip(g, commit, ts)
+		extra = append(extra, "coinflip", commit)
+	}
+	if g.ClockBank > 0 {
+		startClock(g, ts)
+	}
+	if usesSwap2(g.Type) {
+		initOpening(g)
+	}
+	EmitGameJoined(g.ID, joiner, wants, ts, extra...)
 	return nil
 }
 
@@ -67,44 +111,124 @@ func JoinGame(payload *string) *string {
 // updates winner/draw state, and emits move events.
 // Swap2 logic is guarded, so normal moves cannot interfere
 // while the opening phase is still running.
+// Connect6 turns carry two stones: "id|row|col|row|col".
+// Cube boards (Qubic) add the layer in front: "id|layer|row|col".
+// PopOut players may pop their bottom disc instead: "id|pop|col".
+// Order and Chaos moves name the symbol to place: "id|row|col|symbol".
+// Once all pieces are placed (Tapatan) a turn moves one piece:
+// "id|fromRow|fromCol|toRow|toCol".
 //
 //go:wasmexport g_move
 func MakeMove(payload *string) *string {
 	in := *payload
 	gameID := parseU64Fast(nextField(&in))
-	row := int(parseU8Fast(nextField(&in)))
-	col := int(parseU8Fast(nextField(&in)))
-	require(in == "", "too many arguments")
-
 	sender := *sdk.GetEnvKey("msg.sender")
 	g := loadGame(gameID)
 
+	popCol := -1
+	if g.Type == PopOut && strings.HasPrefix(in, "pop|") {
+		nextField(&in)
+		popCol = int(parseU8Fast(nextField(&in)))
+		require(in == "", "too many arguments")
+	}
+
+	// one coordinate per board axis and stone
+	shape := boardShape(g)
+	var coords [][]int
+	symbol := Empty
+	for popCol < 0 {
+		pos := make([]int, len(shape))
+		for k := range pos {
+			require(in != "", "missing coordinate")
+			pos[k] = int(parseU8Fast(nextField(&in)))
+		}
+		if g.Type == OrderChaos {
+			symbol = parseOrderChaosSymbol(&in)
+		}
+		coords = append(coords, pos)
+		if in == "" {
+			break
+		}
+		require(len(coords) < 2, "too many arguments")
+	}
+
 	require(g.Status == InProgress, "game not in progress")
 	require(isPlayer(g, sender), "not a player")
+	requireAuctionSettled(g, parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp")))
+	requireCoinFlipDecided(g)
 
-	// gate swap2
-	if g.Type == Gomoku || g.Type == GomokuFreestyle {
-		if st := loadSwap2Binary(g.ID); st != nil && st.Phase != swap2PhaseNone {
-			sdk.Abort("opening phase in progress; use g_swap")
-		}
+	// gate swap2 and the other gomoku openings
+	if openingDueSeat(g) != 0 {
+		sdk.Abort("opening phase in progress; use g_swap")
 	}
 
-	rows, cols := boardDimensions(g.Type)
-	require(row >= 0 && row < rows && col >= 0 && col < cols, "invalid move")
+	rows, cols := boardDimensions(g)
+	stones := make([][2]int, 0, len(coords))
+	for _, pos := range coords {
+		for k, v := range pos {
+			require(v >= 0 && v < shape[k], "invalid move")
+		}
+		r, c := shapeToGrid(shape, pos)
+		stones = append(stones, [2]int{r, c})
+	}
 
 	grid, mvCount := reconstructBoard(g)
-	currentTurn := computeCurrentTurn(mvCount)
+	currentTurn := computeCurrentTurn(g, mvCount)
 	mark := requireSenderMark(g, sender)
 	require(mark == currentTurn, "not your turn")
 
-	r, c := applyMoveOnGrid(g, grid, row, col, mark)
-	newMv := appendMoveCommit(g, mvCount, r, c, mark)
 	ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
-	EmitGameMoveMade(g.ID, sender, uint8(r*cols+c), ts)
+	chargeClock(g, sender, ts)
+	lapseDrawOffer(g, sender)
+	if popCol >= 0 {
+		applyPop(grid, popCol, mark)
+		appendMoveCommit(g, mvCount, rows-1, popCol, mark|popMoveFlag)
+		EmitGamePopMade(g.ID, sender, uint16((rows-1)*cols+popCol), ts)
+		finalizePop(g, grid, popCol, mark, mvCount+1, ts)
+		return nil
+	}
 
-	if finalizeIfWinOrDraw(g, grid, r, c, mark, newMv, ts) {
+	if inMovementPhase(g, mvCount) {
+		require(len(stones) == 2, "move needs from and to")
+		from, to := stones[0], stones[1]
+		applySlide(g, grid, from, to, mark)
+		appendMoveCommit(g, mvCount, to[0], to[1], mark|slideMoveFlag, from)
+		EmitGamePieceMoved(g.ID, sender, uint16(from[0]*cols+from[1]), uint16(to[0]*cols+to[1]), ts)
+		finalizeIfWinOrDraw(g, grid, to[0], to[1], mark, mvCount+1, ts)
 		return nil
 	}
+
+	perTurn := stonesPerTurn(g, mvCount)
+	require(len(stones) <= perTurn, "too many arguments")
+	require(len(stones) == perTurn, "two stones required this turn")
+
+	// the placed symbol is the player's own mark unless the move names one
+	stone := mark
+	if symbol != Empty {
+		stone = symbol
+	} else if g.Type == Notakto {
+		stone = X
+	}
+
+	placed := make([][2]int, 0, len(stones))
+	for i, st := range stones {
+		r, c := applyMoveOnGrid(g, grid, st[0], st[1], stone)
+		var captured [][2]int
+		if g.Type == Pente {
+			captured = penteCapture(grid, r, c, mark)
+			g.addCaptures(mark, uint16(len(captured)))
+		}
+		appendMoveCommit(g, mvCount+uint64(i), r, c, stone, captured...)
+		EmitGameMoveMade(g.ID, sender, uint16(r*cols+c), ts, wideCells(g), symbol, cellIndices(captured, cols)...)
+		placed = append(placed, [2]int{r, c})
+	}
+
+	// every stone of the turn may complete a line
+	for i, p := range placed {
+		if finalizeIfWinOrDraw(g, grid, p[0], p[1], mark, mvCount+uint64(i)+1, ts) {
+			return nil
+		}
+	}
 	return nil
 }
 
@@ -126,31 +250,34 @@ func ClaimTimeout(payload *string) *string {
 	require(g.PlayerO != nil, "cannot timeout without opponent")
 
 	now := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
-	require(now > g.LastMoveAt+gameTimeout, "timeout not reached")
-
-	// Swap2 case
-	if g.Type == Gomoku || g.Type == GomokuFreestyle {
-		if st := loadSwap2Binary(g.ID); st != nil && st.Phase != swap2PhaseNone {
-			if st.NextActor == 1 {
-				// X due → O wins
-				winner := *g.PlayerO
-				require(sender == winner, "only winning player can claim timeout")
-				finishGameTimeoutCommon(g, winner, g.PlayerX)
-				clearSwap2(g.ID)
-				return nil
-			}
-			// O due → X wins
-			winner := g.PlayerX
+	if st, pending := coinFlipPending(g); pending {
+		claimCoinFlipForfeit(g, st, sender, now)
+		return nil
+	}
+	requireAuctionSettled(g, now)
+	require(timeoutReached(g, now), "timeout not reached")
+
+	// Opening case (swap2 or another gomoku opening)
+	if seat := openingDueSeat(g); seat != 0 {
+		if seat == 1 {
+			// X due → O wins
+			winner := *g.PlayerO
 			require(sender == winner, "only winning player can claim timeout")
-			finishGameTimeoutCommon(g, winner, *g.PlayerO)
-			clearSwap2(g.ID)
+			finishGameTimeoutCommon(g, winner, g.PlayerX)
+			clearAnyOpening(g.ID)
 			return nil
 		}
+		// O due → X wins
+		winner := g.PlayerX
+		require(sender == winner, "only winning player can claim timeout")
+		finishGameTimeoutCommon(g, winner, *g.PlayerO)
+		clearAnyOpening(g.ID)
+		return nil
 	}
 
-	// Normal parity timeout
+	// Normal turn timeout
 	moves := readMoveCount(g.ID)
-	expect := nextToPlay(moves)
+	expect := computeCurrentTurn(g, moves)
 	if expect == X {
 		// X due → O wins
 		w := *g.PlayerO
@@ -177,47 +304,46 @@ func Resign(payload *string) *string {
 
 	sender := sdk.GetEnvKey("msg.sender")
 	g := loadGame(gameId)
-	require(g.Status != Finished, "game is already finished")
+	require(g.Status == WaitingForPlayer || g.Status == InProgress, "game is already over")
 	require(isPlayer(g, *sender), "not part of the game")
 
 	if g.PlayerO == nil {
-		// No opponent yet → remove from waiting, refund if any
-		if g.GameBetAmount != nil {
-			transferPot(g, g.Creator)
-		}
+		// No opponent yet → same as cancelling the lobby
+		ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
+		cancelLobby(g, ts)
+		EmitGameCancelled(g.ID, cancelByCreator, ts)
+		return nil
+	}
 
-		g.Status = Finished
-		g.Winner = nil
+	// Active: the other player wins, unsettled auction bids go back
+	refundAuction(g, parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp")))
+	var winner string
+	if *sender == g.PlayerX {
+		winner = *g.PlayerO
 	} else {
-		// Active: the other player wins
-		var winner string
-		if *sender == g.PlayerX {
-			winner = *g.PlayerO
-		} else {
-			winner = g.PlayerX
-		}
-		g.Status = Finished
-		g.Winner = &winner
-		if g.GameBetAmount != nil {
-			transferPot(g, *g.Winner)
-		}
-
+		winner = g.PlayerX
+	}
+	g.Status = Finished
+	g.Winner = &winner
+	if g.GameBetAmount != nil {
+		transferPot(g, *g.Winner)
 	}
 
 	g.LastMoveAt = parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
 	saveStateBinary(g)
-	clearSwap2(g.ID)
+	clearAnyOpening(g.ID)
 	EmitGameResigned(g.ID, *sender, g.LastMoveAt)
-	if g.Winner != nil {
-		EmitGameWon(g.ID, *g.Winner, g.LastMoveAt)
-	}
+	EmitGameWon(g.ID, *g.Winner, g.LastMoveAt)
 
 	return nil
 }
 
 // SwapMove processes swap2 opening sub-moves:
 // place initial stones, choose swap/stay/add, extra stones, or color.
-// Only valid during Gomoku opening and turn-restricted.
+// Games created with another opening rule are handed to openingSwapMove
+// (place, choose, declare, offer, pick). Pie rule games accept a single
+// "choose|swap" from O right after the first move.
+// Only valid during Gomoku opening or a pie swap and turn-restricted.
 //
 //go:wasmexport g_swap
 //go:wasmexport g_swap
@@ -228,9 +354,26 @@ func SwapMove(payload *string) *string {
 	require(op != "", "missing swap operation")
 
 	g := loadGame(gameID)
-	require(g.Type == Gomoku || g.Type == GomokuFreestyle, "swap only for gomoku")
+	require(usesSwap2(g.Type) || g.Pie, "swap only for gomoku or pie rule games")
 	require(g.Opponent != nil && g.PlayerO != nil, "opponent required")
 	require(g.Status == InProgress, "game not in progress")
+	requireAuctionSettled(g, parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp")))
+	requireCoinFlipDecided(g)
+	// a sub-move out of turn aborts below and takes these with it
+	chargeClock(g, *sdk.GetEnvKey("msg.sender"), parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp")))
+	lapseDrawOffer(g, *sdk.GetEnvKey("msg.sender"))
+
+	if g.Pie {
+		ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
+		pieSwapMove(g, *sdk.GetEnvKey("msg.sender"), op, in, ts)
+		return nil
+	}
+
+	if g.Opening != OpeningSwap2 {
+		ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
+		openingSwapMove(g, *sdk.GetEnvKey("msg.sender"), op, in, ts)
+		return nil
+	}
 
 	st := loadSwap2Binary(g.ID)
 	require(st != nil && st.Phase != swap2PhaseNone, "not in opening")
@@ -238,7 +381,7 @@ func SwapMove(payload *string) *string {
 	sender := *sdk.GetEnvKey("msg.sender")
 	require(sender == st.Actor(g), "not your opening turn")
 
-	_, cols := boardDimensions(g.Type)
+	_, cols := boardDimensions(g)
 	ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
 
 	switch op {
@@ -266,7 +409,7 @@ func SwapMove(payload *string) *string {
 			row := int(parseU8Fast(rowStr))
 			col := int(parseU8Fast(colStr))
 			color := uint8(parseU8Fast(colorStr))
-			cell := uint8(row*cols + col)
+			cell := uint16(row*cols + col)
 
 			EmitSwapEvent(g.ID, sender, "place", &cell, &color, nil, ts)
 		}
@@ -303,7 +446,7 @@ func SwapMove(payload *string) *string {
 			row := int(parseU8Fast(rowStr))
 			col := int(parseU8Fast(colStr))
 			color := uint8(parseU8Fast(colorStr))
-			cell := uint8(row*cols + col)
+			cell := uint16(row*cols + col)
 
 			EmitSwapEvent(g.ID, sender, "add", &cell, &color, nil, ts)
 		}
@@ -328,9 +471,174 @@ func SwapMove(payload *string) *string {
 	return nil
 }
 
+// BidFirstMove bids in a first-move auction: "id" with a transfer
+// intent (ascending), "id|commitment" with a deposit intent and later
+// "id|reveal|amount|salt" (sealed). Anyone may settle a finished
+// auction with "id|settle".
+//
+//go:wasmexport g_bid
+func BidFirstMove(payload *string) *string {
+	in := *payload
+	gameID := parseU64Fast(nextField(&in))
+
+	g := loadGame(gameID)
+	require(g.Status == InProgress, "game not in progress")
+	st, running := auctionRunning(g)
+	require(running, "no first-move auction running")
+
+	sender := *sdk.GetEnvKey("msg.sender")
+	now := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
+	if in == "settle" {
+		require(auctionOver(g, st, now), "auction still running")
+		settleAuction(g, st, now)
+		return nil
+	}
+	require(isPlayer(g, sender), "not a player")
+	placeBid(g, st, sender, in, now)
+	return nil
+}
+
+// CancelGame ends a game without a result: "id". The creator may cancel
+// a lobby nobody joined; either player may abort a joined game before
+// the first stone, unless the first move was already paid for or a coin
+// flip secret revealed. All stakes are refunded.
+//
+//go:wasmexport g_cancel
+func CancelGame(payload *string) *string {
+	in := *payload
+	gameID := parseU64Fast(nextField(&in))
+	require(in == "", "too many arguments")
+
+	sender := *sdk.GetEnvKey("msg.sender")
+	g := loadGame(gameID)
+	ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
+	reason := cancelByCreator
+	switch g.Status {
+	case WaitingForPlayer:
+		require(sender == g.Creator, "only the creator can cancel")
+		cancelLobby(g, ts)
+	case InProgress:
+		require(isPlayer(g, sender), "not part of the game")
+		requireAbortable(g, ts)
+		abortGame(g, ts)
+		reason = cancelAborted
+	default:
+		sdk.Abort("game is already over")
+	}
+	EmitGameCancelled(g.ID, reason, ts)
+	return nil
+}
+
+// ExpireLobby closes a lobby whose join deadline passed: "id".
+// Anyone may call it; the creator's stake is refunded.
+//
+//go:wasmexport g_expire
+func ExpireLobby(payload *string) *string {
+	in := *payload
+	gameID := parseU64Fast(nextField(&in))
+	require(in == "", "too many arguments")
+
+	g := loadGame(gameID)
+	require(g.Status == WaitingForPlayer, "game is not waiting for a player")
+	ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
+	require(lobbyExpired(g, ts), "lobby has not expired")
+
+	cancelLobby(g, ts)
+	EmitGameCancelled(g.ID, cancelExpired, ts)
+	return nil
+}
+
+// DeclineGame lets an invited account refuse a private lobby: "id".
+// When nobody invited is left, the lobby closes and the creator's
+// stake is refunded.
+//
+//go:wasmexport g_decline
+func DeclineGame(payload *string) *string {
+	in := *payload
+	gameID := parseU64Fast(nextField(&in))
+	require(in == "", "too many arguments")
+
+	g := loadGame(gameID)
+	require(g.Status == WaitingForPlayer, "game is not waiting for a player")
+
+	sender := *sdk.GetEnvKey("msg.sender")
+	ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
+	closed := declineInvite(g, sender, ts)
+	EmitGameDeclined(g.ID, sender, closed, ts)
+	if closed {
+		EmitGameCancelled(g.ID, cancelDeclined, ts)
+	}
+	return nil
+}
+
+// OfferDraw offers the opponent a draw: "id".
+//
+//go:wasmexport g_offer_draw
+func OfferDraw(payload *string) *string {
+	in := *payload
+	gameID := parseU64Fast(nextField(&in))
+	require(in == "", "too many arguments")
+
+	g := loadGame(gameID)
+	sender := *sdk.GetEnvKey("msg.sender")
+	offerDraw(g, sender)
+	EmitDrawOffered(g.ID, sender, parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp")))
+	return nil
+}
+
+// AcceptDraw accepts the opponent's draw offer: "id". The pot is split.
+//
+//go:wasmexport g_accept_draw
+func AcceptDraw(payload *string) *string {
+	in := *payload
+	gameID := parseU64Fast(nextField(&in))
+	require(in == "", "too many arguments")
+
+	g := loadGame(gameID)
+	acceptDraw(g, *sdk.GetEnvKey("msg.sender"), parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp")))
+	return nil
+}
+
+// DeclineDraw turns down the opponent's draw offer: "id".
+//
+//go:wasmexport g_decline_draw
+func DeclineDraw(payload *string) *string {
+	in := *payload
+	gameID := parseU64Fast(nextField(&in))
+	require(in == "", "too many arguments")
+
+	g := loadGame(gameID)
+	sender := *sdk.GetEnvKey("msg.sender")
+	takeDrawOffer(g, sender)
+	EmitDrawDeclined(g.ID, sender, parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp")))
+	return nil
+}
+
+// RevealCoinFlip reveals a player's coin flip secret: "id|secretHex".
+// The second reveal decides who plays X.
+//
+//go:wasmexport g_reveal
+func RevealCoinFlip(payload *string) *string {
+	in := *payload
+	gameID := parseU64Fast(nextField(&in))
+	secret := nextField(&in)
+	require(in == "", "too many arguments")
+
+	g := loadGame(gameID)
+	require(g.Status == InProgress, "game not in progress")
+	st, pending := coinFlipPending(g)
+	require(pending, "no coin flip pending")
+
+	sender := *sdk.GetEnvKey("msg.sender")
+	require(isPlayer(g, sender), "not a player")
+	revealCoinFlip(g, st, sender, secret, parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp")))
+	return nil
+}
+
 // GetGame returns a compact string describing match metadata
 // followed by a flat ASCII board. Used by clients to render
-// state without replaying the game engine logic.
+// state without replaying the game engine logic. Some variants
+// append extra "|key=value" fields after the board.
 //
 //go:wasmexport g_get
 func GetGame(payload *string) *string {
@@ -339,16 +647,13 @@ func GetGame(payload *string) *string {
 	require(in == "", "to many arguments")
 
 	g := loadGame(gameId)
-	rows, cols := boardDimensions(g.Type)
+	rows, cols := boardDimensions(g)
 
 	// Recompute grid and move count
 	grid, mvCount := reconstructBoard(g)
 
-	// Compute "turn" from parity (UI only)
-	turn := uint8(1)
-	if mvCount%2 == 1 {
-		turn = 2
-	}
+	// Compute "turn" from move count (UI only)
+	turn := uint8(computeCurrentTurn(g, mvCount))
 
 	meta := make([]byte, 0, 64+len(g.Name)+64)
 	meta = appendU64(meta, g.ID)
@@ -396,6 +701,84 @@ func GetGame(payload *string) *string {
 
 	boardASCII := asciiFromGrid(grid)
 	out := append(meta, []byte(boardASCII)...)
+
+	// variant extras follow the board as |key=value fields
+	if g.Type == Custom {
+		out = appendKVFields(out, ruleFields(g.Rules))
+	}
+	if g.Type == UltimateTicTacToe {
+		out = append(out, "|sub="...)
+		out = append(out, asciiFromGrid(ultimateMetaGrid(grid))...)
+		out = append(out, "|act="...)
+		if act := ultimateActiveBoard(g, grid); act >= 0 && g.Status == InProgress {
+			out = appendU8(out, uint8(act))
+		}
+	}
+	if g.JoinDeadline > 0 && g.Status == WaitingForPlayer {
+		out = append(out, "|expires="...)
+		out = append(out, UInt64ToString(g.JoinDeadline)...)
+	}
+	if g.MoveTimeout > 0 {
+		out = append(out, "|timeout="...)
+		out = append(out, UInt64ToString(uint64(g.MoveTimeout))...)
+	}
+	out = appendKVFields(out, topologyFields(g))
+	out = appendKVFields(out, auctionFields(g))
+	out = appendKVFields(out, coinFlipFields(g))
+	out = appendKVFields(out, clockFields(g))
+	if g.Status == InProgress {
+		if by := loadDrawOffer(g.ID); by != "" {
+			out = append(out, "|drawoffer="...)
+			out = append(out, by...)
+		}
+	}
+	if g.Opening != OpeningSwap2 {
+		out = append(out, "|opening="...)
+		out = append(out, openingNames[g.Opening]...)
+		if st := loadOpening(g.ID); st != nil {
+			out = append(out, "|ostep="...)
+			out = append(out, openingPhaseName(g, st)...)
+			if len(st.Offered) > 0 {
+				out = append(out, "|offers="...)
+				out = appendU16List(out, st.Offered)
+			}
+		}
+	}
+	if g.Pie {
+		out = append(out, "|pie="...)
+		switch {
+		case pieSwappedAt(g.ID) > 0:
+			out = append(out, "swapped"...)
+		case pieAvailable(g, mvCount):
+			out = append(out, "open"...)
+		default:
+			out = append(out, "closed"...)
+		}
+	}
+	if g.Rules.Pieces > 0 {
+		out = append(out, "|phase="...)
+		if inMovementPhase(g, mvCount) {
+			out = append(out, "move"...)
+		} else {
+			out = append(out, "place"...)
+		}
+	}
+	if g.Type == Notakto {
+		out = append(out, "|dead="...)
+		out = append(out, notaktoDeadBoards(g, grid)...)
+	}
+	if g.Rules.Layers > 1 {
+		out = append(out, "|layers="...)
+		out = appendU8(out, g.Rules.Layers)
+	}
+	if g.Type == Pente {
+		out = append(out, "|cx="...)
+		out = appendU16(out, g.capturesOf(X))
+		out = append(out, "|co="...)
+		out = appendU16(out, g.capturesOf(O))
+		out = append(out, "|cap="...)
+		out = appendCellList(out, capturedCellsOf(g), cols)
+	}
 	s := string(out)
 	return &s
 }
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+
+	"okinoko-in_a_row/sdk"
+)
+
+//
+// First-move auction.
+//
+// Instead of a fixed first-move price, a betting game may let both
+// players bid for X after the join. Bids are paid in the game token and
+// held by the contract. Once the auction closes, the highest bidder gets
+// X, the winning bid goes to the other player and every other escrowed
+// amount is refunded. A tie, or no bids at all, keeps the seats as they
+// are (creator X).
+//
+// Ascending: during the window each bid tops up the bidder's escrow and
+// must beat the other player's total.
+// Sealed: during the window each player commits once to
+// sha256("<amount>|<salt>") (hex) with a deposit that covers the bid,
+// then reveals "<amount>|<salt>" during a second window of equal length.
+// An unrevealed bid counts as zero; its deposit is refunded.
+//
+// No move, swap or timeout is possible before the auction is settled.
+// Settlement happens through g_bid "settle" or lazily on the next g_move.
+//
+
+// Auction modes.
+const (
+	auctionNone      uint8 = 0
+	auctionSealed    uint8 = 1
+	auctionAscending uint8 = 2
+)
+
+var auctionNames = [...]string{auctionNone: "none", auctionSealed: "sealed", auctionAscending: "ascending"}
+
+// Bid window limits in seconds.
+const (
+	auctionDefaultWindow = 24 * 3600
+	auctionMinWindow     = 60
+	auctionMaxWindow     = 7 * 24 * 3600 // kept apart from the per-game move timeout
+)
+
+// auctionState is the escrow and bid book of a running auction.
+// Index 0 is the creator, 1 the joiner.
+type auctionState struct {
+	Ends     uint64    // bidding (sealed: commit) window closes
+	Settled  uint64    // settlement time, 0 while running
+	Deposit  [2]uint64 // escrowed amount per bidder
+	Bid      [2]uint64 // ascending: current bid, sealed: revealed bid
+	Revealed [2]bool   // sealed: bid revealed
+	Commit   [2][]byte // sealed: sha256 commitment, nil = no bid
+}
+
+func auctionKey(id uint64) string { return "g_" + UInt64ToString(id) + "_auction" }
+
+// applyAuctionOption handles the "auction" and "auctionwindow" create options.
+func applyAuctionOption(g *Game, key, val string) {
+	switch key {
+	case "auction":
+		g.Auction = nameIndex(auctionNames[:], val, "invalid auction value")
+		if g.AuctionWindow == 0 {
+			g.AuctionWindow = auctionDefaultWindow
+		}
+	case "auctionwindow":
+		n := parseU64Fast(val)
+		require(val != "" && n >= auctionMinWindow && n <= auctionMaxWindow, "invalid auctionwindow value")
+		g.AuctionWindow = uint32(n)
+	}
+}
+
+// validateAuction runs once the bet is known: the auction needs a token
+// to bid in and replaces the fixed first-move price.
+func validateAuction(g *Game) {
+	if g.Auction == auctionNone {
+		require(g.AuctionWindow == 0, "auctionwindow needs an auction")
+		return
+	}
+	require(g.GameAsset != nil, "first-move auction only available in betting games")
+	require(g.FirstMoveCosts == nil || *g.FirstMoveCosts == 0, "first-move auction replaces the first-move price")
+}
+
+// encodeAuction packs mode and window: mode u8, window u32.
+func encodeAuction(g *Game) []byte {
+	return binary.BigEndian.AppendUint32([]byte{g.Auction}, g.AuctionWindow)
+}
+
+// decodeAuction is the inverse of encodeAuction.
+func decodeAuction(g *Game, b []byte) {
+	require(len(b) == 5 && int(b[0]) < len(auctionNames), "invalid auction")
+	g.Auction = b[0]
+	g.AuctionWindow = binary.BigEndian.Uint32(b[1:])
+}
+
+// startAuction opens the bid window when the game is joined.
+func startAuction(g *Game, ts uint64) {
+	saveAuction(g.ID, &auctionState{Ends: ts + uint64(g.AuctionWindow)})
+}
+
+// saveAuction stores the state as ends, settled, then per bidder
+// deposit, bid, revealed flag and a length-prefixed commitment.
+func saveAuction(id uint64, st *auctionState) {
+	out := binary.BigEndian.AppendUint64(nil, st.Ends)
+	out = binary.BigEndian.AppendUint64(out, st.Settled)
+	for i := 0; i < 2; i++ {
+		out = binary.BigEndian.AppendUint64(out, st.Deposit[i])
+		out = binary.BigEndian.AppendUint64(out, st.Bid[i])
+		out = append(out, boolByte(st.Revealed[i]), byte(len(st.Commit[i])))
+		out = append(out, st.Commit[i]...)
+	}
+	sdk.StateSetObject(auctionKey(id), string(out))
+}
+
+// loadAuction reads the auction state, nil if the game never had one.
+func loadAuction(id uint64) *auctionState {
+	ptr := sdk.StateGetObject(auctionKey(id))
+	if ptr == nil || *ptr == "" {
+		return nil
+	}
+	r := &rd{b: []byte(*ptr)}
+	st := &auctionState{Ends: r.u64(), Settled: r.u64()}
+	for i := 0; i < 2; i++ {
+		st.Deposit[i] = r.u64()
+		st.Bid[i] = r.u64()
+		st.Revealed[i] = r.u8() == 1
+		if n := int(r.u8()); n > 0 {
+			st.Commit[i] = r.bytes(n)
+		}
+	}
+	return st
+}
+
+// auctionRunning reports whether a game still waits for its auction.
+func auctionRunning(g *Game) (*auctionState, bool) {
+	if g.Auction == auctionNone {
+		return nil, false
+	}
+	st := loadAuction(g.ID)
+	return st, st != nil && st.Settled == 0
+}
+
+// auctionOver reports whether bidding (and for sealed bids, revealing)
+// is finished. Sealed auctions end early once every commitment is revealed.
+func auctionOver(g *Game, st *auctionState, now uint64) bool {
+	if now <= st.Ends {
+		return false
+	}
+	if g.Auction == auctionAscending {
+		return true
+	}
+	if now > st.Ends+uint64(g.AuctionWindow) {
+		return true
+	}
+	for i := 0; i < 2; i++ {
+		if st.Commit[i] != nil && !st.Revealed[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// requireAuctionSettled settles a finished auction and aborts while
+// one is still running. Called before anything that depends on the seats.
+func requireAuctionSettled(g *Game, now uint64) {
+	st, running := auctionRunning(g)
+	if !running {
+		return
+	}
+	require(auctionOver(g, st, now), "first-move auction running; use g_bid")
+	settleAuction(g, st, now)
+}
+
+// bidderIndex maps a player to their slot in the bid book.
+func bidderIndex(g *Game, addr string) int {
+	if addr == g.Creator {
+		return 0
+	}
+	return 1
+}
+
+// bidderAddr is the inverse of bidderIndex.
+func bidderAddr(g *Game, i int) string {
+	if i == 0 {
+		return g.Creator
+	}
+	return *g.Opponent
+}
+
+// drawBid pulls the bid amount from the caller's transfer intent.
+func drawBid(g *Game) uint64 {
+	ta := GetFirstTransferAllow(sdk.GetEnv().Intents)
+	require(ta != nil, "intent missing")
+	require(ta.Token == *g.GameAsset, "wrong bid token")
+	amt := uint64(math.Round(ta.Limit * 1000))
+	require(amt > 0, "bid must be positive")
+	sdk.HiveDraw(int64(amt), ta.Token)
+	return amt
+}
+
+// auctionCommitHash hashes a sealed bid the way bidders must commit to it.
+func auctionCommitHash(amount, salt string) []byte {
+	h := sha256.Sum256([]byte(amount + "|" + salt))
+	return h[:]
+}
+
+// placeBid handles one g_bid call by a player while the auction runs.
+func placeBid(g *Game, st *auctionState, sender string, in string, now uint64) {
+	i := bidderIndex(g, sender)
+	op := nextField(&in)
+
+	if g.Auction == auctionAscending {
+		require(op == "", "too many arguments")
+		require(now <= st.Ends, "bidding closed")
+		st.Deposit[i] += drawBid(g)
+		st.Bid[i] = st.Deposit[i]
+		require(st.Bid[i] > st.Bid[1-i], "bid must beat the other player")
+		saveAuction(g.ID, st)
+		EmitAuctionBid(g.ID, sender, "bid", UInt64ToString(st.Bid[i]), now)
+		return
+	}
+
+	if op == "reveal" {
+		amount := nextField(&in)
+		salt := nextField(&in)
+		require(in == "" && amount != "", "expected reveal|amount|salt")
+		require(now > st.Ends, "reveal window not open")
+		require(st.Commit[i] != nil && !st.Revealed[i], "nothing to reveal")
+		require(string(auctionCommitHash(amount, salt)) == string(st.Commit[i]), "reveal does not match commitment")
+		// amounts are thousandths of the asset; 19 digits keep it within uint64
+		require(isDigits(amount) && len(amount) <= 19, "invalid bid amount")
+		bid := parseU64Fast(amount)
+		require(bid <= st.Deposit[i], "bid exceeds deposit")
+		st.Bid[i] = bid
+		st.Revealed[i] = true
+		saveAuction(g.ID, st)
+		EmitAuctionBid(g.ID, sender, "reveal", amount, now)
+		return
+	}
+
+	require(in == "", "too many arguments")
+	require(now <= st.Ends, "bidding closed")
+	require(st.Commit[i] == nil, "already committed")
+	commit, err := hex.DecodeString(op)
+	require(err == nil && len(commit) == sha256.Size, "invalid commitment")
+	st.Commit[i] = commit
+	st.Deposit[i] = drawBid(g)
+	saveAuction(g.ID, st)
+	EmitAuctionBid(g.ID, sender, "commit", "", now)
+}
+
+// settleAuction awards X, pays the winning bid to the other player and
+// refunds everything else held in escrow.
+func settleAuction(g *Game, st *auctionState, now uint64) {
+	win := -1
+	if st.Bid[0] > st.Bid[1] {
+		win = 0
+	} else if st.Bid[1] > st.Bid[0] {
+		win = 1
+	}
+
+	refund := st.Deposit
+	price := uint64(0)
+	if win >= 0 {
+		price = st.Bid[win]
+		refund[win] -= price
+		sdk.HiveTransfer(sdk.Address(bidderAddr(g, 1-win)), int64(price), *g.GameAsset)
+		winner := bidderAddr(g, win)
+		if g.PlayerX != winner {
+			swapSeats(g)
+			saveStateBinary(g)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if refund[i] > 0 {
+			sdk.HiveTransfer(sdk.Address(bidderAddr(g, i)), int64(refund[i]), *g.GameAsset)
+		}
+	}
+
+	st.Settled = now
+	saveAuction(g.ID, st)
+	if readMoveCount(g.ID) == 0 && now > g.LastMoveAt {
+		g.LastMoveAt = now
+	}
+	EmitAuctionSettled(g.ID, g.PlayerX, price, now)
+}
+
+// refundAuction returns all escrowed bids, used when a game ends
+// before its auction was settled.
+func refundAuction(g *Game, now uint64) {
+	st, running := auctionRunning(g)
+	if !running {
+		return
+	}
+	for i := 0; i < 2; i++ {
+		if st.Deposit[i] > 0 {
+			sdk.HiveTransfer(sdk.Address(bidderAddr(g, i)), int64(st.Deposit[i]), *g.GameAsset)
+		}
+	}
+	st.Settled = now
+	saveAuction(g.ID, st)
+}
+
+// auctionFields lists the auction for g_get: mode, close of the bid
+// window, and the bids once they are public.
+func auctionFields(g *Game) []string {
+	if g.Auction == auctionNone {
+		return nil
+	}
+	kv := []string{"auction", auctionNames[g.Auction]}
+	st := loadAuction(g.ID)
+	if st == nil {
+		return kv
+	}
+	kv = append(kv, "ends", UInt64ToString(st.Ends))
+	if g.Auction == auctionAscending || st.Settled > 0 {
+		kv = append(kv, "bids", UInt64ToString(st.Bid[0])+","+UInt64ToString(st.Bid[1]))
+	}
+	return kv
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+)
+
+//
+// Blocked-cell (obstacle) boards.
+//
+// Some cells can be blocked for the whole game: nobody may play there
+// and lines can't pass through. The creator either lists the cells at
+// g_create, or asks for a number of random ones that are drawn at
+// g_join from the join transaction, so neither player can pick them.
+// Blocked cells are kept as flat row*cols+col indices in the meta blob
+// and laid onto the grid before moves are replayed.
+//
+
+// supportsBlocked reports whether obstacles may be used with a game type.
+// Sub-boards, cubes and popping columns don't mix with fixed obstacles.
+func supportsBlocked(gt GameType) bool {
+	switch gt {
+	case UltimateTicTacToe, Notakto, Qubic, PopOut:
+		return false
+	}
+	return true
+}
+
+// maxBlocked caps obstacles at a quarter of the board. Piece-limited
+// games also need room for every piece plus one empty cell to slide to.
+func maxBlocked(g *Game) int {
+	rows, cols := boardDimensions(g)
+	n := rows * cols / 4
+	if p := int(g.Rules.Pieces); p > 0 {
+		n = min(n, max(rows*cols-2*p-1, 0))
+	}
+	return n
+}
+
+// applyBlockedOption handles the "blocked" (cell list) and "blockseed"
+// (number of random cells) create options. Board bounds are checked by
+// validateBlocked once every option is applied.
+func applyBlockedOption(g *Game, key, val string) {
+	require(supportsBlocked(g.Type), "option "+key+" not available for this game")
+	require(len(g.Blocked) == 0 && g.BlockCount == 0, "only one of blocked and blockseed allowed")
+	require(val != "", "missing value for "+key)
+	if key == "blockseed" {
+		n := parseU64Fast(val)
+		require(n >= 1 && n <= 0xFF, "invalid blockseed value")
+		g.BlockCount = uint8(n)
+		return
+	}
+
+	for _, f := range strings.Split(val, ",") {
+		require(f != "", "invalid blocked value")
+		cell := parseU64Fast(f)
+		require(cell <= 0xFFFF, "blocked cell off board")
+		require(!containsU16(g.Blocked, uint16(cell)), "blocked cell listed twice")
+		g.Blocked = append(g.Blocked, uint16(cell))
+	}
+}
+
+// validateBlocked checks the obstacles against the final board, since
+// rows= and cols= may still resize a custom board after the option.
+func validateBlocked(g *Game) {
+	rows, cols := boardDimensions(g)
+	for _, cell := range g.Blocked {
+		require(int(cell) < rows*cols, "blocked cell off board")
+	}
+	require(len(g.Blocked) <= maxBlocked(g), "too many blocked cells")
+	require(int(g.BlockCount) <= maxBlocked(g), "invalid blockseed value")
+}
+
+// seedBlockedCells draws the random obstacles once the game is joined.
+// The seed is the hash of the join's tx.id and block.id, re-hashed
+// whenever more bytes are needed, so the result is reproducible.
+func seedBlockedCells(g *Game, txID, blockID string) {
+	if g.BlockCount == 0 || len(g.Blocked) > 0 {
+		return
+	}
+	rows, cols := boardDimensions(g)
+	cells := uint16(rows * cols)
+	h := sha256.Sum256([]byte(txID + "|" + blockID))
+	for i := 0; len(g.Blocked) < int(g.BlockCount); i += 2 {
+		if i+2 > len(h) {
+			h = sha256.Sum256(h[:])
+			i = 0
+		}
+		cell := binary.BigEndian.Uint16(h[i:i+2]) % cells
+		if !containsU16(g.Blocked, cell) {
+			g.Blocked = append(g.Blocked, cell)
+		}
+	}
+}
+
+// applyBlockedMask lays the obstacles onto a fresh grid.
+func applyBlockedMask(g *Game, grid [][]Cell) {
+	_, cols := boardDimensions(g)
+	for _, cell := range g.Blocked {
+		grid[int(cell)/cols][int(cell)%cols] = Blocked
+	}
+}
+
+// encodeBlocked packs the seed count and the blocked cells:
+// count u8, then 2-byte cell indices.
+func encodeBlocked(g *Game) []byte {
+	out := []byte{g.BlockCount}
+	for _, cell := range g.Blocked {
+		out = binary.BigEndian.AppendUint16(out, cell)
+	}
+	return out
+}
+
+// decodeBlocked is the inverse of encodeBlocked.
+func decodeBlocked(g *Game, b []byte) {
+	require(len(b) >= 1 && len(b)%2 == 1, "invalid blocked cells")
+	g.BlockCount = b[0]
+	g.Blocked = nil
+	for i := 1; i < len(b); i += 2 {
+		g.Blocked = append(g.Blocked, binary.BigEndian.Uint16(b[i:i+2]))
+	}
+}
+
+// containsU16 reports whether v is in list.
+func containsU16(list []uint16, v uint16) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package main
+
+//
+// Lobby expiry and cancellation.
+//
+// Games that end without a result get a status of their own, Cancelled,
+// so indexers can drop them instead of guessing from a finished game
+// without winner. Every stake is refunded. This happens when
+//   - the creator cancels a lobby nobody joined (g_cancel or g_resign),
+//   - a lobby passes its join deadline and anyone closes it (g_expire),
+//   - the last invitee of a private lobby declines (g_decline),
+//   - a player aborts a joined game before the first stone (g_cancel).
+//
+// An abort is refused once the first move was paid for or decided in a
+// way that can't be undone: a first-move fee went to the creator, the
+// auction winner paid their bid, or a coin flip secret was revealed.
+// Otherwise a player could keep the payment, or dodge a flip they see
+// going against them, and walk away with their stake.
+//
+
+// Join deadline limits in seconds after creation.
+const (
+	lobbyMinExpiry = 60
+	lobbyMaxExpiry = 90 * 24 * 3600
+)
+
+// Cancellation reasons carried by the "x" event.
+const (
+	cancelExpired   = "expired"
+	cancelByCreator = "cancelled"
+	cancelAborted   = "aborted"
+	cancelDeclined  = "declined"
+)
+
+// applyExpiryOption handles the "expiry" create option (seconds until
+// the lobby may be closed).
+func applyExpiryOption(g *Game, val string) {
+	n := parseU64Fast(val)
+	require(val != "" && n >= lobbyMinExpiry && n <= lobbyMaxExpiry, "invalid expiry value")
+	g.JoinDeadline = g.CreatedAt + n
+}
+
+// lobbyExpired reports whether a lobby's join deadline has passed.
+func lobbyExpired(g *Game, now uint64) bool {
+	return g.JoinDeadline > 0 && now > g.JoinDeadline
+}
+
+// cancelLobby ends a game nobody joined: the creator's stake goes back
+// and the game is marked Cancelled.
+func cancelLobby(g *Game, ts uint64) {
+	if g.GameBetAmount != nil {
+		transferPot(g, g.Creator)
+	}
+	g.Status = Cancelled
+	g.LastMoveAt = ts
+	saveStateBinary(g)
+}
+
+// requireAbortable aborts the call unless a joined game may still be
+// abandoned: no stone yet, and nothing paid or revealed for the first
+// move. An auction that is over settles first, so its price counts.
+func requireAbortable(g *Game, now uint64) {
+	require(readMoveCount(g.ID) == 0, "game already started")
+	paidFee := g.FirstMoveCosts != nil && *g.FirstMoveCosts > 0 && g.PlayerX != g.Creator
+	require(!paidFee, "first move was bought")
+	if st, running := auctionRunning(g); running && auctionOver(g, st, now) {
+		settleAuction(g, st, now)
+	}
+	if g.Auction != auctionNone {
+		if st := loadAuction(g.ID); st != nil && st.Settled > 0 {
+			require(st.Bid[0] == st.Bid[1], "first move was auctioned")
+		}
+	}
+	if g.CoinFlip != nil {
+		if st := loadCoinFlip(g.ID); st != nil {
+			require(st.Secret[0] == nil && st.Secret[1] == nil, "coin flip already revealed")
+		}
+	}
+}
+
+// abortGame ends a joined game before any stone was placed: both stakes
+// and any escrowed auction bids go back.
+func abortGame(g *Game, ts uint64) {
+	refundAuction(g, ts)
+	if g.GameBetAmount != nil {
+		splitPot(g)
+	}
+	g.Status = Cancelled
+	g.LastMoveAt = ts
+	saveStateBinary(g)
+	clearAnyOpening(g.ID)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/binary"
+
+	"okinoko-in_a_row/sdk"
+)
+
+//
+// Time controls.
+//
+// A player who lets the move timeout pass loses on g_timeout. It is
+// gameTimeout (7 days) unless the creator picks another one with the
+// "timeout" create option, from a minute for blitz games on fast blocks
+// up to 30 days for correspondence play.
+//
+// Instead of a timeout per move, a game may run a chess clock: each
+// player starts with the same time bank ("clock") and gets a Fischer
+// increment ("increment") back after every move. The time a player
+// spends on g_move or g_swap is taken from their bank, measured in
+// block timestamps from the moment their turn began. A move that comes
+// after the bank ran out is refused, and the opponent claims the win
+// with g_timeout. The banks are cached per player (creator, joiner) so
+// seat swaps during the opening carry them along.
+//
+
+// Move timeout limits in seconds.
+const (
+	minMoveTimeout = 60
+	maxMoveTimeout = 30 * 24 * 3600
+)
+
+// applyTimeoutOption handles the "timeout" create option.
+func applyTimeoutOption(g *Game, val string) {
+	n := parseU64Fast(val)
+	require(val != "" && n >= minMoveTimeout && n <= maxMoveTimeout, "invalid timeout value")
+	g.MoveTimeout = uint32(n)
+}
+
+// moveTimeout returns how long a player may take for a move.
+func moveTimeout(g *Game) uint64 {
+	if g.MoveTimeout > 0 {
+		return uint64(g.MoveTimeout)
+	}
+	return gameTimeout
+}
+
+// maxClockIncrement caps the Fischer increment; the bank itself takes
+// the move timeout limits.
+const maxClockIncrement = 24 * 3600
+
+// clockState caches the banks and when the running clock started.
+// Index 0 is the creator, 1 the joiner.
+type clockState struct {
+	Since uint64    // last time a clock was charged (or the join)
+	Left  [2]uint64 // remaining bank per player
+}
+
+func clockKey(id uint64) string { return "g_" + UInt64ToString(id) + "_clock" }
+
+// applyClockOption handles the "clock" and "increment" create options.
+func applyClockOption(g *Game, key, val string) {
+	n := parseU64Fast(val)
+	switch key {
+	case "clock":
+		require(val != "" && n >= minMoveTimeout && n <= maxMoveTimeout, "invalid clock value")
+		g.ClockBank = uint32(n)
+	case "increment":
+		require(val != "" && n <= maxClockIncrement, "invalid increment value")
+		g.ClockIncrement = uint32(n)
+	}
+}
+
+// validateClock runs after all options: the increment needs a clock and
+// a clock replaces the per-move timeout.
+func validateClock(g *Game) {
+	if g.ClockBank == 0 {
+		require(g.ClockIncrement == 0, "increment needs a clock")
+		return
+	}
+	require(g.MoveTimeout == 0, "clock can't be combined with timeout")
+}
+
+// encodeClock packs bank and increment: two u32.
+func encodeClock(g *Game) []byte {
+	return binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, g.ClockBank), g.ClockIncrement)
+}
+
+// decodeClock is the inverse of encodeClock.
+func decodeClock(g *Game, b []byte) {
+	require(len(b) == 8, "invalid clock")
+	g.ClockBank = binary.BigEndian.Uint32(b)
+	g.ClockIncrement = binary.BigEndian.Uint32(b[4:])
+}
+
+// startClock fills both banks when the game is joined.
+func startClock(g *Game, ts uint64) {
+	bank := uint64(g.ClockBank)
+	saveClock(g.ID, &clockState{Since: ts, Left: [2]uint64{bank, bank}})
+}
+
+// saveClock stores since, then both banks, each as u64.
+func saveClock(id uint64, st *clockState) {
+	out := binary.BigEndian.AppendUint64(nil, st.Since)
+	out = binary.BigEndian.AppendUint64(out, st.Left[0])
+	out = binary.BigEndian.AppendUint64(out, st.Left[1])
+	sdk.StateSetObject(clockKey(id), string(out))
+}
+
+// loadClock reads the clock state, nil if the game has no clock.
+func loadClock(id uint64) *clockState {
+	ptr := sdk.StateGetObject(clockKey(id))
+	if ptr == nil || *ptr == "" {
+		return nil
+	}
+	r := &rd{b: []byte(*ptr)}
+	return &clockState{Since: r.u64(), Left: [2]uint64{r.u64(), r.u64()}}
+}
+
+// clockStart is when the due player's clock started: the last charge,
+// or later if a settled auction, coin flip or pie swap moved LastMoveAt.
+func clockStart(g *Game, st *clockState) uint64 {
+	if g.LastMoveAt > st.Since {
+		return g.LastMoveAt
+	}
+	return st.Since
+}
+
+// clockUsed returns the seconds the due player has spent so far.
+func clockUsed(g *Game, st *clockState, now uint64) uint64 {
+	start := clockStart(g, st)
+	if now <= start {
+		return 0
+	}
+	return now - start
+}
+
+// chargeClock takes the time spent from the mover's bank and adds the
+// increment. A failing move aborts and takes the charge with it.
+func chargeClock(g *Game, player string, now uint64) {
+	if g.ClockBank == 0 {
+		return
+	}
+	st := loadClock(g.ID)
+	require(st != nil, "clock missing")
+	i := bidderIndex(g, player)
+	used := clockUsed(g, st, now)
+	require(used <= st.Left[i], "out of time")
+	st.Left[i] = st.Left[i] - used + uint64(g.ClockIncrement)
+	st.Since = now
+	saveClock(g.ID, st)
+}
+
+// duePlayer returns the wallet whose turn it is, opening sub-moves included.
+func duePlayer(g *Game) string {
+	if seat := openingDueSeat(g); seat != 0 {
+		return seatPlayer(g, seat)
+	}
+	if computeCurrentTurn(g, readMoveCount(g.ID)) == X {
+		return g.PlayerX
+	}
+	return *g.PlayerO
+}
+
+// timeoutReached reports whether the player due may be claimed against:
+// the move timeout passed, or with a clock, their bank ran out.
+func timeoutReached(g *Game, now uint64) bool {
+	if g.ClockBank == 0 {
+		return now > g.LastMoveAt+moveTimeout(g)
+	}
+	st := loadClock(g.ID)
+	require(st != nil, "clock missing")
+	return clockUsed(g, st, now) > st.Left[bidderIndex(g, duePlayer(g))]
+}
+
+// clockFields lists the clock for g_get: the banks of X and O as of the
+// last move, and when the running clock started.
+func clockFields(g *Game) []string {
+	if g.ClockBank == 0 {
+		return nil
+	}
+	kv := []string{"clock", UInt64ToString(uint64(g.ClockBank)) + "+" + UInt64ToString(uint64(g.ClockIncrement))}
+	st := loadClock(g.ID)
+	if st == nil || g.PlayerO == nil {
+		return kv
+	}
+	x, o := st.Left[bidderIndex(g, g.PlayerX)], st.Left[bidderIndex(g, *g.PlayerO)]
+	return append(kv, "left", UInt64ToString(x)+","+UInt64ToString(o), "since", UInt64ToString(clockStart(g, st)))
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+
+	"okinoko-in_a_row/sdk"
+)
+
+//
+// Commit-reveal coin flip for the first move.
+//
+// The creator commits to sha256(secret) at g_create, the joiner at
+// g_join; secrets are 32 bytes written as hex. Once joined, both reveal
+// their secret with g_reveal. The XOR of the two secrets decides the
+// seats: an even last byte keeps the creator on X, an odd one gives X to
+// the joiner. Neither side can steer the result without knowing the
+// other's secret in advance.
+//
+// Whoever doesn't reveal before the deadline forfeits: the other player
+// claims the win with g_timeout. If neither reveals, the claim ends the
+// game as a draw. Moves and opening sub-moves wait for the flip, so a
+// swap2 or scripted opening simply starts with the seats it decided.
+//
+
+// coinFlipWindow is how long both players have to reveal after the join.
+const coinFlipWindow = 24 * 3600
+
+// coinFlipState tracks the reveals. Index 0 is the creator, 1 the joiner.
+type coinFlipState struct {
+	Deadline uint64    // reveals close
+	Decided  uint64    // time the seats were decided, 0 while open
+	Commit   []byte    // joiner's commitment (the creator's is in meta)
+	Secret   [2][]byte // revealed secrets, nil = not yet
+}
+
+func coinFlipKey(id uint64) string { return "g_" + UInt64ToString(id) + "_flip" }
+
+// parseSha256Hex decodes a hex sha256 digest or aborts with msg.
+func parseSha256Hex(val, msg string) []byte {
+	b, err := hex.DecodeString(val)
+	require(err == nil && len(b) == sha256.Size, msg)
+	return b
+}
+
+// parseCoinFlipCommit decodes a hex sha256 commitment.
+func parseCoinFlipCommit(val string) []byte {
+	return parseSha256Hex(val, "invalid coinflip commitment")
+}
+
+// applyCoinFlipOption handles the "coinflip" create option.
+func applyCoinFlipOption(g *Game, val string) {
+	g.CoinFlip = parseCoinFlipCommit(val)
+}
+
+// validateCoinFlip makes sure the coin flip is the only thing deciding seats.
+func validateCoinFlip(g *Game) {
+	if g.CoinFlip == nil {
+		return
+	}
+	require(g.FirstMoveCosts == nil || *g.FirstMoveCosts == 0, "coinflip can't be combined with first-move purchase")
+	require(g.Auction == auctionNone, "coinflip can't be combined with an auction")
+}
+
+// startCoinFlip stores the joiner's commitment and opens the reveals.
+func startCoinFlip(g *Game, commit string, ts uint64) {
+	saveCoinFlip(g.ID, &coinFlipState{Deadline: ts + coinFlipWindow, Commit: parseCoinFlipCommit(commit)})
+}
+
+// saveCoinFlip stores deadline, decided, the joiner's commitment and
+// both secrets, each secret behind a presence byte.
+func saveCoinFlip(id uint64, st *coinFlipState) {
+	out := binary.BigEndian.AppendUint64(nil, st.Deadline)
+	out = binary.BigEndian.AppendUint64(out, st.Decided)
+	out = append(out, st.Commit...)
+	for _, s := range st.Secret {
+		out = append(out, boolByte(s != nil))
+		out = append(out, s...)
+	}
+	sdk.StateSetObject(coinFlipKey(id), string(out))
+}
+
+// loadCoinFlip reads the flip state, nil if the game has none.
+func loadCoinFlip(id uint64) *coinFlipState {
+	ptr := sdk.StateGetObject(coinFlipKey(id))
+	if ptr == nil || *ptr == "" {
+		return nil
+	}
+	r := &rd{b: []byte(*ptr)}
+	st := &coinFlipState{Deadline: r.u64(), Decided: r.u64(), Commit: r.bytes(sha256.Size)}
+	for i := range st.Secret {
+		if r.u8() == 1 {
+			st.Secret[i] = r.bytes(sha256.Size)
+		}
+	}
+	return st
+}
+
+// coinFlipPending returns the flip state while seats are still open.
+func coinFlipPending(g *Game) (*coinFlipState, bool) {
+	if g.CoinFlip == nil {
+		return nil, false
+	}
+	st := loadCoinFlip(g.ID)
+	return st, st != nil && st.Decided == 0
+}
+
+// requireCoinFlipDecided aborts while the coin flip is still open.
+func requireCoinFlipDecided(g *Game) {
+	_, pending := coinFlipPending(g)
+	require(!pending, "coin flip pending; use g_reveal")
+}
+
+// revealCoinFlip checks a player's secret against their commitment and
+// decides the seats once both secrets are known.
+func revealCoinFlip(g *Game, st *coinFlipState, sender, secret string, now uint64) {
+	require(now <= st.Deadline, "reveal deadline passed")
+	i := bidderIndex(g, sender)
+	require(st.Secret[i] == nil, "already revealed")
+	b, err := hex.DecodeString(secret)
+	require(err == nil && len(b) == sha256.Size, "invalid secret")
+	commit := g.CoinFlip
+	if i == 1 {
+		commit = st.Commit
+	}
+	h := sha256.Sum256(b)
+	require(string(h[:]) == string(commit), "secret does not match commitment")
+	st.Secret[i] = b
+
+	x := ""
+	if st.Secret[0] != nil && st.Secret[1] != nil {
+		if (st.Secret[0][31]^st.Secret[1][31])&1 == 1 && g.PlayerX == g.Creator {
+			swapSeats(g)
+			saveStateBinary(g)
+		}
+		st.Decided = now
+		x = g.PlayerX
+	}
+	saveCoinFlip(g.ID, st)
+	EmitCoinFlipReveal(g.ID, sender, secret, x, now)
+}
+
+// claimCoinFlipForfeit ends a game whose reveal deadline passed: the only
+// player who revealed wins, with no reveal at all it is a draw.
+func claimCoinFlipForfeit(g *Game, st *coinFlipState, sender string, now uint64) {
+	require(now > st.Deadline, "reveal deadline not reached")
+	switch {
+	case st.Secret[0] != nil:
+		require(sender == g.Creator, "only the revealing player can claim")
+		finishGameTimeoutCommon(g, g.Creator, *g.Opponent)
+	case st.Secret[1] != nil:
+		require(sender == *g.Opponent, "only the revealing player can claim")
+		finishGameTimeoutCommon(g, *g.Opponent, g.Creator)
+	default:
+		g.LastMoveAt = now
+		declareDraw(g, now)
+	}
+	clearAnyOpening(g.ID)
+}
+
+// coinFlipFields lists the flip for g_get: state and reveal deadline.
+func coinFlipFields(g *Game) []string {
+	if g.CoinFlip == nil {
+		return nil
+	}
+	st := loadCoinFlip(g.ID)
+	switch {
+	case st == nil:
+		return []string{"flip", "waiting"}
+	case st.Decided == 0:
+		return []string{"flip", "pending", "flipends", UInt64ToString(st.Deadline)}
+	}
+	return []string{"flip", "done"}
+}
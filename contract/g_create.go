@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/hex"
 	"okinoko-in_a_row/sdk"
 	"strings"
 )
@@ -40,28 +41,41 @@ func initNewGame(gt GameType, name string, sender string, ts uint64, gameId uint
 		PlayerO:        nil,
 		Status:         WaitingForPlayer,
 		Winner:         nil,
+		CreatedAt:      ts,
 		LastMoveAt:     ts,
 		FirstMoveCosts: &firstMoveCost,
+		Rules:          presetRules(gt),
 	}
 }
 
-// parseCreateArgs splits the raw input payload into type, name and optional fee.
+// createOption is one trailing "key=value" field of the create payload.
+// Kept as a slice (not a map) so options are applied in payload order.
+type createOption struct {
+	Key   string
+	Value string
+}
+
+// parseCreateArgs splits the raw input payload into type, name, optional fee
+// and any trailing "key=value" rule options.
 // Rejects bad arguments early so the game is not created with odd state.
 // The first-move cost is stored as a fixed-point number (3 decimal places).
-func parseCreateArgs(payload *string) (gt GameType, name string, fmc uint64) {
+func parseCreateArgs(payload *string) (gt GameType, name string, fmc uint64, opts []createOption) {
 	in := *payload
 	typStr := nextField(&in)
 	name = nextField(&in)
 	fmcString := nextField(&in)
 
-	require(in == "", "too many arguments")
+	for in != "" {
+		field := nextField(&in)
+		eq := strings.IndexByte(field, '=')
+		// a trailing field without '=' is one argument too many, not an option
+		require(eq > 0, "too many arguments")
+		opts = append(opts, createOption{Key: field[:eq], Value: field[eq+1:]})
+	}
 	require(!strings.Contains(name, "|"), "name must not contain '|'") // not necessary but cleaner
 
 	gt = GameType(parseU8Fast(typStr))
-	require(
-		gt == TicTacToe || gt == ConnectFour || gt == Gomoku || gt == TicTacToe5 || gt == Squava || gt == GomokuFreestyle,
-		"invalid type",
-	)
+	require(isValidGameType(gt), "invalid type")
 
 	if fmcString != "" {
 		fmc = parseFixedPoint3(fmcString)
@@ -69,6 +83,197 @@ func parseCreateArgs(payload *string) (gt GameType, name string, fmc uint64) {
 	return
 }
 
+// isValidGameType reports whether gt is a rule set this contract can play.
+func isValidGameType(gt GameType) bool {
+	switch gt {
+	case TicTacToe, ConnectFour, Gomoku, TicTacToe5, Squava, GomokuFreestyle, Renju, Pente, Connect6, Custom, UltimateTicTacToe, Qubic, PopOut, OrderChaos, Notakto, Tapatan, Teeko:
+		return true
+	}
+	return false
+}
+
+// applyCreateOptions copies the optional rule settings onto the new game.
+// Unknown keys or options that don't fit the game type abort the create.
+func applyCreateOptions(g *Game, opts []createOption) {
+	for _, o := range opts {
+		switch o.Key {
+		case "fb":
+			// renju: what happens to a forbidden black move
+			require(g.Type == Renju, "option fb only available for renju")
+			switch o.Value {
+			case "reject":
+				g.ForbiddenLoses = false
+			case "lose":
+				g.ForbiddenLoses = true
+			default:
+				sdk.Abort("invalid fb value")
+			}
+		case "misere":
+			require(supportsMisere(g.Type), "option misere not available for this game")
+			require(o.Value == "0" || o.Value == "1", "invalid misere value")
+			g.Rules.Misere = o.Value == "1"
+		case "boards":
+			require(g.Type == Notakto, "option boards only available for notakto")
+			n := parseU64Fast(o.Value)
+			require(o.Value != "" && n >= notaktoMinBoards && n <= notaktoMaxBoards, "invalid boards value")
+			g.Rules = notaktoRules(uint8(n))
+		case "pieces":
+			// tapatan plays with 3 pieces each, achi with 4
+			require(g.Type == Tapatan, "option pieces only available for tapatan")
+			require(o.Value == "3" || o.Value == "4", "invalid pieces value")
+			g.Rules.Pieces = uint8(parseU8Fast(o.Value))
+		case "wrap", "fall":
+			applyTopologyOption(g, o.Key, o.Value)
+		case "blocked", "blockseed":
+			applyBlockedOption(g, o.Key, o.Value)
+		case "opening":
+			applyOpeningOption(g, o.Value)
+		case "auction", "auctionwindow":
+			applyAuctionOption(g, o.Key, o.Value)
+		case "invite":
+			applyInviteOption(g, o.Value)
+		case "clock", "increment":
+			applyClockOption(g, o.Key, o.Value)
+		case "timeout":
+			applyTimeoutOption(g, o.Value)
+		case "expiry":
+			applyExpiryOption(g, o.Value)
+		case "password":
+			g.JoinHash = parseSha256Hex(o.Value, "invalid password hash")
+		case "coinflip":
+			applyCoinFlipOption(g, o.Value)
+		case "pie":
+			require(supportsPie(g.Type), "option pie not available for this game")
+			require(o.Value == "0" || o.Value == "1", "invalid pie value")
+			g.Pie = o.Value == "1"
+		case "rows", "cols", "win", "exact", "gravity", "lose":
+			require(g.Type == Custom, "option "+o.Key+" only available for custom games")
+			applyCustomRule(&g.Rules, o.Key, o.Value)
+		default:
+			sdk.Abort("unknown option: " + o.Key)
+		}
+	}
+	if g.Type == Custom {
+		validateCustomRules(g.Rules)
+	}
+	validateBlocked(g)
+	if openingCentered(g.Opening) {
+		require(len(g.Blocked) == 0 && g.BlockCount == 0, "opening "+openingNames[g.Opening]+" not available with blocked cells")
+	}
+}
+
+// Custom board limits. Anything above 256 cells uses the 16-bit
+// move encoding; 32x32 keeps the board replay affordable.
+const (
+	customMinSide = 3
+	customMaxSide = 32
+)
+
+// applyCustomRule sets one field of a custom ruleset from its option.
+func applyCustomRule(rs *Ruleset, key, val string) {
+	require(val != "", "missing value for "+key)
+	n := parseU64Fast(val)
+	require(n <= 255, "invalid "+key+" value")
+	switch key {
+	case "rows":
+		rs.Rows = uint8(n)
+	case "cols":
+		rs.Cols = uint8(n)
+	case "win":
+		rs.WinLen = uint8(n)
+	case "exact":
+		require(val == "0" || val == "1", "invalid exact value")
+		rs.Exact = val == "1"
+	case "gravity":
+		require(val == "0" || val == "1", "invalid gravity value")
+		rs.Gravity = val == "1"
+	case "lose":
+		rs.LoseLen = uint8(n)
+	}
+}
+
+// validateCustomRules makes sure a custom board is playable.
+func validateCustomRules(rs Ruleset) {
+	require(rs.Rows >= customMinSide && rs.Rows <= customMaxSide, "invalid rows")
+	require(rs.Cols >= customMinSide && rs.Cols <= customMaxSide, "invalid cols")
+	longest := rs.Rows
+	if rs.Cols > longest {
+		longest = rs.Cols
+	}
+	require(rs.WinLen >= 3 && rs.WinLen <= longest, "invalid win length")
+	require(rs.LoseLen == 0 || (rs.LoseLen >= 2 && rs.LoseLen < rs.WinLen), "invalid lose length")
+}
+
+// createEventOptions lists the rule options worth showing in the "c" event,
+// as flat key/value pairs. Defaults are left out to keep the log small.
+func createEventOptions(g *Game) []string {
+	var kv []string
+	if g.ForbiddenLoses {
+		kv = append(kv, "fb", "lose")
+	}
+	if g.Type == Custom {
+		kv = append(kv, ruleFields(g.Rules)...)
+	}
+	if g.Rules.Misere {
+		kv = append(kv, "misere", "1")
+	}
+	kv = append(kv, topologyFields(g)...)
+	if len(g.Blocked) > 0 {
+		kv = append(kv, "blocked", string(appendU16List(nil, g.Blocked)))
+	}
+	if g.BlockCount > 0 {
+		kv = append(kv, "blockseed", UInt64ToString(uint64(g.BlockCount)))
+	}
+	if g.Rules.Pieces != presetRules(g.Type).Pieces {
+		kv = append(kv, "pieces", UInt64ToString(uint64(g.Rules.Pieces)))
+	}
+	if g.Opening != OpeningSwap2 {
+		kv = append(kv, "opening", openingNames[g.Opening])
+	}
+	if g.Pie {
+		kv = append(kv, "pie", "1")
+	}
+	if len(g.Invited) > 0 {
+		kv = append(kv, "invite", strings.Join(g.Invited, ","))
+	}
+	if g.JoinDeadline > 0 {
+		kv = append(kv, "expires", UInt64ToString(g.JoinDeadline))
+	}
+	if g.MoveTimeout > 0 {
+		kv = append(kv, "timeout", UInt64ToString(uint64(g.MoveTimeout)))
+	}
+	if g.ClockBank > 0 {
+		kv = append(kv, "clock", UInt64ToString(uint64(g.ClockBank)), "increment", UInt64ToString(uint64(g.ClockIncrement)))
+	}
+	if g.JoinHash != nil {
+		// only the flag; the hash itself stays out of the log
+		kv = append(kv, "locked", "1")
+	}
+	if g.CoinFlip != nil {
+		kv = append(kv, "coinflip", hex.EncodeToString(g.CoinFlip))
+	}
+	if g.Auction != auctionNone {
+		kv = append(kv, "auction", auctionNames[g.Auction], "auctionwindow", UInt64ToString(uint64(g.AuctionWindow)))
+	}
+	if g.Type == Notakto {
+		kv = append(kv, "boards", UInt64ToString(uint64(notaktoBoards(g))))
+	}
+	return kv
+}
+
+// ruleFields lists a custom ruleset as key/value pairs, using the same
+// keys as the create options.
+func ruleFields(rs Ruleset) []string {
+	return []string{
+		"rows", UInt64ToString(uint64(rs.Rows)),
+		"cols", UInt64ToString(uint64(rs.Cols)),
+		"win", UInt64ToString(uint64(rs.WinLen)),
+		"exact", UInt64ToString(uint64(boolByte(rs.Exact))),
+		"gravity", UInt64ToString(uint64(boolByte(rs.Gravity))),
+		"lose", UInt64ToString(uint64(rs.LoseLen)),
+	}
+}
+
 // applyOptionalBetOnCreate checks if the transaction includes
 // a token transfer that should become the wager for this game.
 // If present we draw the funds and attach them to the game.
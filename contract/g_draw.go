@@ -0,0 +1,70 @@
+package main
+
+import "okinoko-in_a_row/sdk"
+
+//
+// Draw by agreement.
+//
+// A player may offer a draw with g_offer_draw. The offer stands until the
+// opponent accepts it with g_accept_draw, which ends the game as a draw
+// and splits the pot, or declines it with g_decline_draw. Moving instead
+// of answering declines it as well. The offering player may keep moving
+// while the offer stands. Only one offer is open at a time.
+//
+
+func drawOfferKey(id uint64) string { return "g_" + UInt64ToString(id) + "_drawoffer" }
+
+// loadDrawOffer returns who offered a draw, "" if nobody did.
+func loadDrawOffer(id uint64) string {
+	ptr := sdk.StateGetObject(drawOfferKey(id))
+	if ptr == nil {
+		return ""
+	}
+	return *ptr
+}
+
+// clearDrawOffer drops an open offer.
+func clearDrawOffer(id uint64) {
+	sdk.StateSetObject(drawOfferKey(id), "")
+}
+
+// lapseDrawOffer drops an offer made by the mover's opponent.
+func lapseDrawOffer(g *Game, mover string) {
+	if by := loadDrawOffer(g.ID); by != "" && by != mover {
+		clearDrawOffer(g.ID)
+	}
+}
+
+// requireDrawGame checks that sender plays in a running two-player game.
+func requireDrawGame(g *Game, sender string) {
+	require(g.Status == InProgress, "game not in progress")
+	require(g.PlayerO != nil, "opponent required")
+	require(isPlayer(g, sender), "not a player")
+}
+
+// offerDraw opens a draw offer by sender.
+func offerDraw(g *Game, sender string) {
+	requireDrawGame(g, sender)
+	require(loadDrawOffer(g.ID) == "", "draw offer pending")
+	sdk.StateSetObject(drawOfferKey(g.ID), sender)
+}
+
+// takeDrawOffer checks that the opponent of sender has an offer open
+// and drops it.
+func takeDrawOffer(g *Game, sender string) {
+	requireDrawGame(g, sender)
+	by := loadDrawOffer(g.ID)
+	require(by != "", "no draw offer")
+	require(by != sender, "cannot answer your own offer")
+	clearDrawOffer(g.ID)
+}
+
+// acceptDraw ends the game as a draw. Escrowed auction bids go back
+// before the pot is split.
+func acceptDraw(g *Game, sender string, ts uint64) {
+	takeDrawOffer(g, sender)
+	refundAuction(g, ts)
+	g.LastMoveAt = ts
+	declareDraw(g, ts)
+	clearAnyOpening(g.ID)
+}
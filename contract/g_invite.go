@@ -0,0 +1,101 @@
+package main
+
+import (
+	"crypto/sha256"
+	"strings"
+)
+
+//
+// Private lobbies.
+//
+// A creator may name the accounts allowed to join. Anyone else is turned
+// away by g_join. An invited account that doesn't want to play can refuse
+// with g_decline, which takes it off the list; once the last invitee has
+// declined the lobby closes and the creator's stake is refunded.
+//
+// A lobby can also be locked with the sha256 of a password shared out of
+// band; g_join then has to carry the password itself.
+//
+
+// maxInvites caps the invite list of a lobby.
+const maxInvites = 10
+
+// applyInviteOption handles the "invite" create option: one address or
+// a comma-separated list.
+func applyInviteOption(g *Game, val string) {
+	require(len(g.Invited) == 0, "option invite given twice")
+	require(val != "", "missing value for invite")
+	for _, addr := range strings.Split(val, ",") {
+		require(addr != "" && len(addr) <= 255, "invalid invite address")
+		require(addr != g.Creator, "cannot invite yourself")
+		require(!containsString(g.Invited, addr), "address invited twice")
+		g.Invited = append(g.Invited, addr)
+	}
+	require(len(g.Invited) <= maxInvites, "too many invites")
+}
+
+// canJoin reports whether addr may join: open lobbies take anyone.
+func canJoin(g *Game, addr string) bool {
+	return len(g.Invited) == 0 || containsString(g.Invited, addr)
+}
+
+// encodeInvites packs the list as count u8, then length u8 + address each.
+func encodeInvites(list []string) []byte {
+	out := []byte{uint8(len(list))}
+	for _, addr := range list {
+		out = append(out, uint8(len(addr)))
+		out = append(out, addr...)
+	}
+	return out
+}
+
+// decodeInvites is the inverse of encodeInvites.
+func decodeInvites(b []byte) []string {
+	r := &rd{b: b}
+	n := int(r.u8())
+	list := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		list = append(list, string(r.bytes(int(r.u8()))))
+	}
+	return list
+}
+
+// declineInvite takes addr off the invite list and reports whether the
+// lobby closed because nobody invited is left.
+func declineInvite(g *Game, addr string, ts uint64) (closed bool) {
+	require(containsString(g.Invited, addr), "not invited")
+	kept := g.Invited[:0]
+	for _, a := range g.Invited {
+		if a != addr {
+			kept = append(kept, a)
+		}
+	}
+	g.Invited = kept
+	saveMetaBinary(g)
+	if len(g.Invited) > 0 {
+		return false
+	}
+
+	cancelLobby(g, ts)
+	return true
+}
+
+// requireJoinSecret checks the lobby password: its sha256 must match
+// the hash the creator stored. '|' separates payload fields and is never
+// part of a valid password.
+func requireJoinSecret(g *Game, secret string) {
+	require(secret != "", "password required")
+	require(strings.IndexByte(secret, '|') < 0, "password must not contain |")
+	h := sha256.Sum256([]byte(secret))
+	require(string(h[:]) == string(g.JoinHash), "wrong password")
+}
+
+// containsString reports whether v is in list.
+func containsString(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
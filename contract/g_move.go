@@ -58,46 +58,166 @@ func writeMoveCount(id uint64, n uint64) {
 	sdk.StateSetObject(moveCountKey(id), UInt64ToString(n))
 }
 
+// Move record layouts. Version 1 (no marker) stores row and col as
+// single bytes. Boards with more than 256 cells use version 2, which
+// starts with a marker byte and stores 16-bit cell indices instead.
+// A v1 record can never start with the marker since no board has 255 rows.
+const (
+	moveV2Marker byte = 0xFF
+	moveV2       byte = 2
+)
+
+// Move kind flags, or-ed into the stored mark byte which otherwise only
+// holds X or O. Plain placements carry no flag.
+const (
+	popMoveFlag   Cell = 0x80 // popout: own disc removed from the bottom row
+	slideMoveFlag Cell = 0x40 // movement phase: piece moved, from-cell stored as removed cell
+	moveFlagMask       = popMoveFlag | slideMoveFlag
+)
+
+// wideCells reports whether a board's cell indices need 16 bits,
+// which switches moves and events to the version 2 encoding.
+func wideCells(g *Game) bool {
+	rows, cols := boardDimensions(g)
+	return rows*cols > 256
+}
+
 // appendMoveBinary records a move in a compact 7-byte form
 // (row, col, mark, and a 4-byte delta timestamp since game start).
 // Row and col are stored as single bytes to keep storage tight.
-func appendMoveBinary(id uint64, n uint64, row, col int, mark Cell, ts uint64, createdAt uint64) {
-	if ts < createdAt {
+// Large boards use the v2 form (marker, version, 2-byte cell index,
+// mark, delta). Stones removed by the move (pente captures, or the
+// from-cell of a slide) follow as row/col byte pairs (v1) or 2-byte
+// cell indices (v2).
+func appendMoveBinary(g *Game, n uint64, row, col int, mark Cell, ts uint64, captured ...[2]int) {
+	if ts < g.CreatedAt {
 		sdk.Abort("timestamp before game creation")
 	}
-	delta := uint32(ts - createdAt)
+	delta := uint32(ts - g.CreatedAt)
+	_, cols := boardDimensions(g)
+	wide := wideCells(g)
 
-	out := make([]byte, 0, 7)
-	out = append(out, byte(row), byte(col), byte(mark))
+	out := make([]byte, 0, 9+2*len(captured))
+	if wide {
+		out = append(out, moveV2Marker, moveV2)
+		out = binary.BigEndian.AppendUint16(out, uint16(row*cols+col))
+		out = append(out, byte(mark))
+	} else {
+		out = append(out, byte(row), byte(col), byte(mark))
+	}
 
 	var buf [4]byte
 	binary.BigEndian.PutUint32(buf[:], delta)
 	out = append(out, buf[:]...)
 
-	sdk.StateSetObject(moveKey(id, n), string(out))
+	for _, rc := range captured {
+		if wide {
+			out = binary.BigEndian.AppendUint16(out, uint16(rc[0]*cols+rc[1]))
+		} else {
+			out = append(out, byte(rc[0]), byte(rc[1]))
+		}
+	}
+
+	sdk.StateSetObject(moveKey(g.ID, n), string(out))
+}
+
+// readMoveTimestamp loads a move and recovers its absolute timestamp
+// by adding the stored delta to creation time. Works for both layouts
+// without knowing the board.
+func readMoveTimestamp(id uint64, n uint64, createdAt uint64) uint64 {
+	data := readMoveRaw(id, n)
+	off := 3
+	if data[0] == moveV2Marker {
+		off = 5
+	}
+	return createdAt + uint64(binary.BigEndian.Uint32(data[off:off+4]))
 }
 
-// readMoveBinary loads a move and recovers row, col, mark and the
-// absolute timestamp by adding the stored delta to creation time.
-func readMoveBinary(id uint64, n uint64, createdAt uint64) (row, col int, mark Cell, ts uint64) {
+// readMoveRaw fetches the stored bytes of the nth move.
+func readMoveRaw(id uint64, n uint64) []byte {
 	ptr := sdk.StateGetObject(moveKey(id, n))
 	require(ptr != nil && *ptr != "", "move "+UInt64ToString(n)+" missing")
 
 	data := []byte(*ptr)
 	require(len(data) >= 7, "corrupt move data")
+	if data[0] == moveV2Marker {
+		require(len(data) >= 9 && data[1] == moveV2, "corrupt move data")
+	}
+	return data
+}
 
+// decodeMoveBinary splits a stored move into row, col, mark and timestamp.
+func decodeMoveBinary(g *Game, data []byte) (row, col int, mark Cell, ts uint64) {
+	if data[0] == moveV2Marker {
+		_, cols := boardDimensions(g)
+		cell := int(binary.BigEndian.Uint16(data[2:4]))
+		row, col = cell/cols, cell%cols
+		mark = Cell(data[4]) &^ moveFlagMask
+		ts = g.CreatedAt + uint64(binary.BigEndian.Uint32(data[5:9]))
+		return
+	}
 	row = int(data[0])
 	col = int(data[1])
-	mark = Cell(data[2])
+	mark = Cell(data[2]) &^ moveFlagMask
 	delta := binary.BigEndian.Uint32(data[3:7])
-	ts = createdAt + uint64(delta)
+	ts = g.CreatedAt + uint64(delta)
 	return
 }
 
+// moveFlags returns the kind flags of a stored move.
+func moveFlags(data []byte) Cell {
+	if data[0] == moveV2Marker {
+		return Cell(data[4]) & moveFlagMask
+	}
+	return Cell(data[2]) & moveFlagMask
+}
+
+// isPopMove reports whether a stored move popped a disc (PopOut)
+// instead of placing one.
+func isPopMove(data []byte) bool {
+	return moveFlags(data)&popMoveFlag != 0
+}
+
+// isSlideMove reports whether a stored move moved a piece from one
+// cell to another (movement phase) instead of placing a new one.
+func isSlideMove(data []byte) bool {
+	return moveFlags(data)&slideMoveFlag != 0
+}
+
+// decodeMoveCaptures returns the cells a stored move removed from the board.
+// Moves without captures simply end after the fixed part.
+func decodeMoveCaptures(g *Game, data []byte) [][2]int {
+	var out [][2]int
+	if data[0] == moveV2Marker {
+		_, cols := boardDimensions(g)
+		rest := data[9:]
+		require(len(rest)%2 == 0, "corrupt move captures")
+		for i := 0; i < len(rest); i += 2 {
+			cell := int(binary.BigEndian.Uint16(rest[i : i+2]))
+			out = append(out, [2]int{cell / cols, cell % cols})
+		}
+		return out
+	}
+	rest := data[7:]
+	require(len(rest)%2 == 0, "corrupt move captures")
+	for i := 0; i < len(rest); i += 2 {
+		out = append(out, [2]int{int(rest[i]), int(rest[i+1])})
+	}
+	return out
+}
+
 // computeCurrentTurn figures out whose turn it is based on the
 // stored role order and number of moves so far. Needed because
 // roles might swap during join due to first-move purchase.
-func computeCurrentTurn(mvCount uint64) Cell {
+// Connect6 counts stones, not turns: X opens with one stone,
+// then each side places two.
+func computeCurrentTurn(g *Game, mvCount uint64) Cell {
+	if g.Type == Connect6 {
+		if mvCount == 0 || ((mvCount-1)/2)%2 == 1 {
+			return X
+		}
+		return O
+	}
 	// X always starts. Then alternate.
 	turn := X
 	if mvCount%2 == 1 {
@@ -106,109 +226,171 @@ func computeCurrentTurn(mvCount uint64) Cell {
 	return turn
 }
 
+// stonesPerTurn reports how many stones the side to move must place.
+// Always one, except Connect6 after the opening stone. Near the end of
+// a Connect6 game a single remaining cell is enough.
+func stonesPerTurn(g *Game, mvCount uint64) int {
+	if g.Type != Connect6 || mvCount == 0 {
+		return 1
+	}
+	rows, cols := boardDimensions(g)
+	if int(mvCount)+1 >= rows*cols-len(g.Blocked) {
+		return 1
+	}
+	return 2
+}
+
 // applyMoveOnGrid writes a mark (X or O) into the grid.
-// Connect Four drops pieces from the top; point-based boards
-// require the target cell to be empty.
+// Gravity boards (Connect Four) drop pieces towards the fall edge; point-based
+// boards require the target cell to be empty. Renju rejects forbidden
+// black moves here unless the game is set to "forbidden loses".
 func applyMoveOnGrid(g *Game, grid [][]Cell, row, col int, mark Cell) (appliedRow int, appliedCol int) {
-	switch g.Type {
-	case TicTacToe, Gomoku, TicTacToe5, Squava, GomokuFreestyle:
-		require(getCellGrid(grid, row, col) == Empty, "cell occupied")
-		setCellGrid(grid, row, col, mark)
-		return row, col
-	case ConnectFour:
-		r := dropDiscGrid(grid, col)
-		require(r >= 0, "column full")
-		grid[r][col] = mark
-		return r, col
-	default:
-		sdk.Abort("invalid game type")
-	}
-	return -1, -1 // shouldn't reach here
+	if g.Rules.Gravity {
+		r, c := fallGrid(grid, row, col, g.Rules.FallDir)
+		full := "column full"
+		if g.Rules.FallDir == fallLeft || g.Rules.FallDir == fallRight {
+			full = "row full"
+		}
+		require(r >= 0, full)
+		grid[r][c] = mark
+		return r, c
+	}
+
+	require(getCellGrid(grid, row, col) == Empty, "cell occupied")
+	if g.Type == UltimateTicTacToe {
+		ultimateRequireTarget(g, grid, row, col)
+	}
+	if g.Type == Notakto {
+		require(!notaktoDead(grid, row/3), "board is dead")
+	}
+	setCellGrid(grid, row, col, mark)
+	if g.Type == Renju && mark == X && !g.ForbiddenLoses {
+		require(!renjuForbidden(grid, row, col), "forbidden move")
+	}
+	return row, col
 }
 
 // winLengthFor reports the line length needed to win, and whether
 // the line must be exact (gomoku rule).
 func winLengthFor(g *Game) (int, bool) {
-	switch g.Type {
-	case TicTacToe:
-		return 3, false
-	case TicTacToe5:
-		return 4, false
-	case Squava:
-		return 4, false
-	case ConnectFour:
-		return 4, false
-	case Gomoku:
-		return 5, true
-	case GomokuFreestyle:
-		return 5, false
-	default:
-		sdk.Abort("invalid game type")
-	}
-	return 0, false
+	return int(g.Rules.WinLen), g.Rules.Exact
 }
 
 // finalizeIfWinOrDraw checks win/draw conditions, updates game state,
 // handles payouts, emits events, and returns whether the game ended.
-// Some games (Squava) have a "lose by making 3" rule, handled here.
+// Losing lines (Squava's "lose on N", misère) come from the line rules.
+// Renju black only wins with exactly five and loses on forbidden shapes.
 func finalizeIfWinOrDraw(g *Game, grid [][]Cell, row, col int, mark Cell, mvCount uint64, ts uint64) (finished bool) {
-	winLen, exact := winLengthFor(g)
+	if g.Type == UltimateTicTacToe {
+		return finalizeUltimate(g, grid, row, col, mark, ts)
+	}
+	if g.Type == OrderChaos {
+		return finalizeOrderChaos(g, grid, row, col, mvCount, ts)
+	}
+	if g.Type == Notakto {
+		return finalizeNotakto(g, grid, mark, ts)
+	}
 
-	if checkPatternGrid(grid, row, col, winLen, exact) {
-		if mark == X {
-			w := g.PlayerX
-			g.Winner = &w
-		} else {
-			g.Winner = g.PlayerO
+	if g.Type == Renju && mark == X {
+		winLen, _ := winLengthFor(g)
+		if checkPatternGrid(grid, row, col, winLen, true) {
+			declareWinner(g, X, ts)
+			return true
 		}
-		g.Status = Finished
-		if g.GameBetAmount != nil {
-			transferPot(g, *g.Winner)
+		// only reachable when forbidden moves lose, otherwise rejected earlier
+		if renjuForbidden(grid, row, col) {
+			declareWinner(g, O, ts)
+			return true
 		}
-		saveStateBinary(g)
-		EmitGameWon(g.ID, *g.Winner, ts)
+	} else if side := lineOutcome(g, grid, row, col, mark); side != Empty {
+		// win line, or a losing line (squava "lose on 3", misère)
+		declareWinner(g, side, ts)
 		return true
 	}
 
-	// special Squava condition: making 3 in a row loses on the spot
-	if g.Type == Squava && checkPatternGrid(grid, row, col, 3, exact) {
-		if mark == O {
-			w := g.PlayerX
-			g.Winner = &w
-		} else {
-			g.Winner = g.PlayerO
-		}
-		g.Status = Finished
-		if g.GameBetAmount != nil {
-			transferPot(g, *g.Winner)
-		}
-		saveStateBinary(g)
-		EmitGameWon(g.ID, *g.Winner, ts)
+	// piece-limited games never fill up; blocked players and the slide cap end them
+	if g.Rules.Pieces > 0 {
+		return finalizeMovement(g, grid, mark, mvCount, ts)
+	}
+
+	// pente: capturing five pairs wins as well
+	if g.Type == Pente && g.capturesOf(mark) >= penteCaptureWin {
+		declareWinner(g, mark, ts)
 		return true
 	}
 
-	// draw when all cells filled
-	rows, cols := boardDimensions(g.Type)
-	if int(mvCount) >= rows*cols {
-		g.Status = Finished
-		if g.GameBetAmount != nil {
-			splitPot(g)
-		}
-		saveStateBinary(g)
-		EmitGameDraw(g.ID, ts)
+	if popOutCapReached(g, mvCount) {
+		declareDraw(g, ts)
+		return true
+	}
+
+	// draw when all cells filled; in PopOut only if the next player can't pop
+	if boardFull(g, grid, mvCount) && !(g.Type == PopOut && canPop(grid, computeCurrentTurn(g, mvCount))) {
+		declareDraw(g, ts)
 		return true
 	}
 
 	return false
 }
 
+// declareDraw finishes the game without a winner, splits the pot
+// if there is one and emits the draw event.
+func declareDraw(g *Game, ts uint64) {
+	g.Status = Finished
+	if g.GameBetAmount != nil {
+		splitPot(g)
+	}
+	saveStateBinary(g)
+	EmitGameDraw(g.ID, ts)
+}
+
+// boardFull reports whether no empty cell is left. Stones are never
+// removed in most games, so the move count (plus blocked cells) is enough;
+// pente captures and popout pops free cells again and need a scan.
+// On gravity boards an obstacle hides the cells behind it, so the board
+// is full once no line takes another piece.
+func boardFull(g *Game, grid [][]Cell, mvCount uint64) bool {
+	if g.Type == Pente || g.Type == PopOut {
+		for _, row := range grid {
+			for _, c := range row {
+				if c == Empty {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	if g.Rules.Gravity {
+		return !canDrop(grid, g.Rules.FallDir)
+	}
+	rows, cols := boardDimensions(g)
+	return int(mvCount) >= rows*cols-len(g.Blocked)
+}
+
+// declareWinner finishes the game in favour of the given side,
+// pays out the pot if there is one and emits the win event.
+func declareWinner(g *Game, side Cell, ts uint64) {
+	if side == X {
+		w := g.PlayerX
+		g.Winner = &w
+	} else {
+		g.Winner = g.PlayerO
+	}
+	g.Status = Finished
+	if g.GameBetAmount != nil {
+		transferPot(g, *g.Winner)
+	}
+	saveStateBinary(g)
+	EmitGameWon(g.ID, *g.Winner, ts)
+}
+
 // appendMoveCommit stores a move with current timestamp and bumps
 // the move counter. Call this after validating and applying the move.
-func appendMoveCommit(g *Game, mvCount uint64, row, col int, mark Cell) uint64 {
+func appendMoveCommit(g *Game, mvCount uint64, row, col int, mark Cell, captured ...[2]int) uint64 {
 	newID := mvCount + 1
 	tsString := *sdk.GetEnvKey("block.timestamp")
 	unixTS := parseISO8601ToUnix(tsString)
-	appendMoveBinary(g.ID, newID, row, col, mark, unixTS, g.CreatedAt)
+	appendMoveBinary(g, newID, row, col, mark, unixTS, captured...)
 	writeMoveCount(g.ID, newID)
 	return newID
 }
@@ -0,0 +1,89 @@
+package main
+
+//
+// Movement phase helpers (Tapatan / Achi style).
+//
+// Games with a piece limit start like any other: players place their
+// pieces one by one. Once both sides have placed all of them, a turn
+// moves one own piece to an adjacent empty point instead. Slides are
+// stored with the slide flag and their from-cell, so the board can be
+// replayed. A move cap ends endless shuffling in a draw.
+//
+
+// Step kinds: which neighbours a piece may move to.
+const (
+	stepAlquerque uint8 = 1 // orthogonal, diagonals only along the lines through even points
+	stepKing      uint8 = 2 // any of the eight neighbours
+)
+
+// movementMaxSlides is the number of slides (both sides together) after
+// which a movement game without a winner ends in a draw.
+const movementMaxSlides = 100
+
+// inMovementPhase reports whether all pieces are placed, so the next
+// turn must move a piece instead of placing one.
+func inMovementPhase(g *Game, mvCount uint64) bool {
+	return g.Rules.Pieces > 0 && mvCount >= 2*uint64(g.Rules.Pieces)
+}
+
+// stepAllowed reports whether a piece may move from (fr,fc) to (tr,tc)
+// in one step under the game's step kind.
+func stepAllowed(g *Game, fr, fc, tr, tc int) bool {
+	dr, dc := tr-fr, tc-fc
+	if dr < -1 || dr > 1 || dc < -1 || dc > 1 || (dr == 0 && dc == 0) {
+		return false
+	}
+	if dr == 0 || dc == 0 || g.Rules.Step == stepKing {
+		return true
+	}
+	// alquerque board: diagonal lines only connect points with even row+col
+	return (fr+fc)%2 == 0
+}
+
+// applySlide validates and performs a movement-phase move of an own piece
+// to an adjacent empty point.
+func applySlide(g *Game, grid [][]Cell, from, to [2]int, mark Cell) {
+	require(grid[from[0]][from[1]] == mark, "no own piece to move")
+	require(grid[to[0]][to[1]] == Empty, "cell occupied")
+	require(stepAllowed(g, from[0], from[1], to[0], to[1]), "not an adjacent point")
+	grid[from[0]][from[1]] = Empty
+	grid[to[0]][to[1]] = mark
+}
+
+// canSlide reports whether the side has at least one legal move.
+func canSlide(g *Game, grid [][]Cell, side Cell) bool {
+	rows, cols := len(grid), len(grid[0])
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if grid[r][c] != side {
+				continue
+			}
+			for tr := r - 1; tr <= r+1; tr++ {
+				for tc := c - 1; tc <= c+1; tc++ {
+					if tr >= 0 && tr < rows && tc >= 0 && tc < cols && grid[tr][tc] == Empty && stepAllowed(g, r, c, tr, tc) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// finalizeMovement ends a piece-limited game without a line: the next
+// player loses if all their pieces are blocked, and the game is drawn
+// once the slide cap is reached.
+func finalizeMovement(g *Game, grid [][]Cell, mark Cell, mvCount uint64, ts uint64) bool {
+	if !inMovementPhase(g, mvCount) {
+		return false
+	}
+	if !canSlide(g, grid, otherSide(mark)) {
+		declareWinner(g, mark, ts)
+		return true
+	}
+	if mvCount-2*uint64(g.Rules.Pieces) >= movementMaxSlides {
+		declareDraw(g, ts)
+		return true
+	}
+	return false
+}
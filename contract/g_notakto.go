@@ -0,0 +1,51 @@
+package main
+
+//
+// Notakto helpers.
+//
+// Both players place X on one or more 3x3 boards, stacked on top of each
+// other in the grid (board k covers rows 3k..3k+2). A board with a line
+// is dead and takes no more stones. Whoever kills the last board loses.
+//
+
+// Notakto board count limits.
+const (
+	notaktoMinBoards = 1
+	notaktoMaxBoards = 5
+)
+
+// notaktoRules builds the stacked grid for the given number of boards.
+func notaktoRules(boards uint8) Ruleset {
+	return Ruleset{Rows: 3 * boards, Cols: 3, WinLen: 3}
+}
+
+// notaktoBoards returns how many 3x3 boards the game uses.
+func notaktoBoards(g *Game) int {
+	return int(g.Rules.Rows) / 3
+}
+
+// notaktoDead reports whether board b already holds a line.
+func notaktoDead(grid [][]Cell, b int) bool {
+	return subBoardState(grid, b, 0) == X
+}
+
+// notaktoDeadBoards lists the boards as digits, 1 = dead, 0 = alive.
+func notaktoDeadBoards(g *Game, grid [][]Cell) string {
+	out := make([]byte, notaktoBoards(g))
+	for b := range out {
+		out[b] = '0' + boolByte(notaktoDead(grid, b))
+	}
+	return string(out)
+}
+
+// finalizeNotakto ends the game once the last stone killed the last
+// living board; the player who placed it loses.
+func finalizeNotakto(g *Game, grid [][]Cell, mark Cell, ts uint64) bool {
+	for b := 0; b < notaktoBoards(g); b++ {
+		if !notaktoDead(grid, b) {
+			return false
+		}
+	}
+	declareWinner(g, otherSide(mark), ts)
+	return true
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"okinoko-in_a_row/sdk"
+)
+
+//
+// Gomoku opening rules other than swap2.
+//
+// Each rule is a short script of steps. A step names the seat that acts
+// (1 = X seat, 2 = O seat) and what it must do: place stones, decide on a
+// swap, declare how many fifth moves it will offer, or offer them. Seats,
+// not wallets, are scripted, so a swap simply exchanges who sits where and
+// the script carries on. Offered fifth moves are kept in the opening state
+// until the other seat picks one; a pick always ends the opening.
+//
+// Stone colours follow the move count like in normal play (X on even
+// counts), so a placement step never names a colour. Swap2 keeps its own
+// state in g_swap.go and is not scripted here.
+//
+
+// OpeningRule selects the gomoku opening protocol played after g_join.
+type OpeningRule uint8
+
+const (
+	OpeningSwap2       OpeningRule = 0
+	OpeningSwap1       OpeningRule = 1
+	OpeningSoosorv8    OpeningRule = 2
+	OpeningTaraguchi10 OpeningRule = 3
+	OpeningYamaguchi   OpeningRule = 4
+)
+
+var openingNames = [...]string{
+	OpeningSwap2:       "swap2",
+	OpeningSwap1:       "swap1",
+	OpeningSoosorv8:    "soosorv8",
+	OpeningTaraguchi10: "taraguchi10",
+	OpeningYamaguchi:   "yamaguchi",
+}
+
+// Opening step operations.
+const (
+	openingPlace   uint8 = 1 // place len(Area) stones
+	openingSwap    uint8 = 2 // swap seats or stay
+	openingDeclare uint8 = 3 // declare how many fifth moves will be offered
+	openingOffer   uint8 = 4 // offer the declared number of fifth moves
+)
+
+var openingOpNames = [...]string{openingPlace: "place", openingSwap: "choose", openingDeclare: "declare", openingOffer: "offer"}
+
+// openingMaxOffers caps the fifth-move offers any rule may ask for.
+const openingMaxOffers = 10
+
+// openingStep is one scripted action of an opening rule.
+type openingStep struct {
+	Op   uint8   // openingPlace, openingSwap, openingDeclare or openingOffer
+	Seat uint8   // 1 = X seat, 2 = O seat
+	Area []uint8 // place: side of the centered square each stone must be in, 0 = anywhere
+	// declare: highest count allowed. offer: fixed count, 0 = the declared one.
+	// place: when set, the seat may offer that many fifth moves instead.
+	Offers uint8
+}
+
+// openingScripts lists the steps of every scripted opening rule.
+var openingScripts = [...][]openingStep{
+	OpeningSwap1: {
+		{Op: openingPlace, Seat: 1, Area: []uint8{0, 0, 0}},
+		{Op: openingSwap, Seat: 2},
+	},
+	OpeningSoosorv8: {
+		{Op: openingPlace, Seat: 1, Area: []uint8{1, 3, 5}},
+		{Op: openingSwap, Seat: 2},
+		{Op: openingPlace, Seat: 2, Area: []uint8{0}},
+		{Op: openingDeclare, Seat: 2, Offers: 8},
+		{Op: openingSwap, Seat: 1},
+		{Op: openingOffer, Seat: 1},
+	},
+	OpeningTaraguchi10: {
+		{Op: openingPlace, Seat: 1, Area: []uint8{1}},
+		{Op: openingSwap, Seat: 2},
+		{Op: openingPlace, Seat: 2, Area: []uint8{3}},
+		{Op: openingSwap, Seat: 1},
+		{Op: openingPlace, Seat: 1, Area: []uint8{5}},
+		{Op: openingSwap, Seat: 2},
+		{Op: openingPlace, Seat: 2, Area: []uint8{7}},
+		{Op: openingSwap, Seat: 1},
+		{Op: openingPlace, Seat: 1, Area: []uint8{9}, Offers: 10},
+		{Op: openingSwap, Seat: 2},
+	},
+	OpeningYamaguchi: {
+		{Op: openingPlace, Seat: 1, Area: []uint8{1, 3, 5}},
+		{Op: openingDeclare, Seat: 1, Offers: openingMaxOffers},
+		{Op: openingSwap, Seat: 2},
+		{Op: openingPlace, Seat: 2, Area: []uint8{0}},
+		{Op: openingOffer, Seat: 1},
+	},
+}
+
+// openingState is the persisted progress of a scripted opening.
+type openingState struct {
+	Step     uint8    // index into the rule's script
+	Declared uint8    // fifth moves announced by a declare step
+	Offered  []uint16 // offered fifth moves (row*cols+col), waiting for a pick
+}
+
+func openingKey(id uint64) string { return "g_" + UInt64ToString(id) + "_opening" }
+
+// supportsOpening reports whether a game type may pick its opening rule.
+// Renju stays on swap2.
+func supportsOpening(gt GameType) bool {
+	return gt == Gomoku || gt == GomokuFreestyle
+}
+
+// openingCentered reports whether a rule restricts stones to the centre,
+// which a blocked centre could make impossible to play.
+func openingCentered(rule OpeningRule) bool {
+	for _, st := range openingScripts[rule] {
+		for _, a := range st.Area {
+			if a > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyOpeningOption handles the "opening" create option.
+func applyOpeningOption(g *Game, val string) {
+	require(supportsOpening(g.Type), "option opening not available for this game")
+	g.Opening = OpeningRule(nameIndex(openingNames[:], val, "invalid opening value"))
+}
+
+// initOpening starts the opening once a gomoku game is joined:
+// swap2 games get their swap2 state, scripted rules start at step 0.
+func initOpening(g *Game) {
+	if g.Opening == OpeningSwap2 {
+		initSwap2IfGomokuBinary(g)
+		return
+	}
+	saveOpening(g.ID, &openingState{})
+}
+
+// saveOpening stores the state as step, declared, offer count, offers.
+func saveOpening(id uint64, st *openingState) {
+	buf := []byte{st.Step, st.Declared, uint8(len(st.Offered))}
+	for _, cell := range st.Offered {
+		buf = binary.BigEndian.AppendUint16(buf, cell)
+	}
+	sdk.StateSetObject(openingKey(id), string(buf))
+}
+
+// loadOpening returns the scripted opening state, or nil once it is over.
+func loadOpening(id uint64) *openingState {
+	ptr := sdk.StateGetObject(openingKey(id))
+	if ptr == nil || *ptr == "" {
+		return nil
+	}
+	data := []byte(*ptr)
+	require(len(data) >= 3 && len(data) == 3+2*int(data[2]), "invalid opening binary")
+	st := &openingState{Step: data[0], Declared: data[1]}
+	for i := 3; i < len(data); i += 2 {
+		st.Offered = append(st.Offered, binary.BigEndian.Uint16(data[i:i+2]))
+	}
+	return st
+}
+
+// clearOpening removes the scripted opening state.
+func clearOpening(id uint64) {
+	sdk.StateSetObject(openingKey(id), "")
+}
+
+// openingDueSeat returns the seat (1 = X, 2 = O) that must act in a running
+// opening, swap2 included, or 0 when no opening is in progress.
+func openingDueSeat(g *Game) uint8 {
+	if !usesSwap2(g.Type) {
+		return 0
+	}
+	if g.Opening == OpeningSwap2 {
+		if st := loadSwap2Binary(g.ID); st != nil && st.Phase != swap2PhaseNone {
+			return st.NextActor
+		}
+		return 0
+	}
+	st := loadOpening(g.ID)
+	if st == nil {
+		return 0
+	}
+	return openingSeat(g, st)
+}
+
+// openingSeat is the seat due for the current step. Pending offers are
+// picked by the seat that did not offer them.
+func openingSeat(g *Game, st *openingState) uint8 {
+	seat := openingScripts[g.Opening][st.Step].Seat
+	if len(st.Offered) > 0 {
+		return 3 - seat
+	}
+	return seat
+}
+
+// seatPlayer returns the wallet sitting at a seat.
+func seatPlayer(g *Game, seat uint8) string {
+	if seat == 1 {
+		return g.PlayerX
+	}
+	return *g.PlayerO
+}
+
+// clearAnyOpening drops whatever opening state a game still has.
+func clearAnyOpening(id uint64) {
+	clearSwap2(id)
+	clearOpening(id)
+}
+
+// openingPhaseName describes what the opening expects next, for g_get.
+func openingPhaseName(g *Game, st *openingState) string {
+	if len(st.Offered) > 0 {
+		return "pick"
+	}
+	return openingOpNames[openingScripts[g.Opening][st.Step].Op]
+}
+
+// openingAdvance moves to the next step, ending the opening after the last.
+func openingAdvance(g *Game, st *openingState) {
+	st.Step++
+	if int(st.Step) >= len(openingScripts[g.Opening]) {
+		clearOpening(g.ID)
+		return
+	}
+	saveOpening(g.ID, st)
+}
+
+// openingInArea reports whether (row,col) lies in the centered square of
+// the given side. Zero allows the whole board.
+func openingInArea(g *Game, row, col int, area uint8) bool {
+	if area == 0 {
+		return true
+	}
+	rows, cols := boardDimensions(g)
+	half := int(area) / 2
+	dr, dc := row-rows/2, col-cols/2
+	return dr >= -half && dr <= half && dc >= -half && dc <= half
+}
+
+// parseOpeningCell reads a "row-col" pair and checks it is on the board.
+func parseOpeningCell(g *Game, s string) (int, int) {
+	parts := strings.Split(s, "-")
+	require(len(parts) == 2, "invalid cell (expected row-col)")
+	row, col := int(parseU8Fast(parts[0])), int(parseU8Fast(parts[1]))
+	rows, cols := boardDimensions(g)
+	require(row < rows && col < cols, "invalid coord")
+	return row, col
+}
+
+// openingSwapMove runs one g_swap sub-move of a scripted opening.
+// Ops: place|r-c|..., choose|swap|stay, declare|N, offer|r-c|..., pick|r-c.
+func openingSwapMove(g *Game, sender, op string, in string, ts uint64) {
+	st := loadOpening(g.ID)
+	require(st != nil, "not in opening")
+	require(sender == seatPlayer(g, openingSeat(g, st)), "not your opening turn")
+
+	step := openingScripts[g.Opening][st.Step]
+	_, cols := boardDimensions(g)
+	var args []string
+	for in != "" {
+		args = append(args, nextField(&in))
+	}
+
+	if len(st.Offered) > 0 {
+		require(op == "pick", "wrong phase")
+		require(len(args) == 1, "pick needs one cell")
+		row, col := parseOpeningCell(g, args[0])
+		cell := uint16(row*cols + col)
+		require(containsU16(st.Offered, cell), "cell was not offered")
+		_, mv := reconstructBoard(g)
+		appendMoveCommit(g, mv, row, col, computeCurrentTurn(g, mv))
+		clearOpening(g.ID)
+		EmitSwapEvent(g.ID, sender, "pick", &cell, nil, nil, ts)
+		return
+	}
+
+	switch op {
+	case "place":
+		require(step.Op == openingPlace, "wrong phase")
+		require(len(args) == len(step.Area), "expected "+UInt64ToString(uint64(len(step.Area)))+" stones")
+		grid, mv := reconstructBoard(g)
+		for i, a := range args {
+			row, col := parseOpeningCell(g, a)
+			require(grid[row][col] == Empty, "cell occupied")
+			require(openingInArea(g, row, col, step.Area[i]), "stone outside the allowed area")
+			mark := computeCurrentTurn(g, mv)
+			setCellGrid(grid, row, col, mark)
+			mv = appendMoveCommit(g, mv, row, col, mark)
+			cell := uint16(row*cols + col)
+			color := uint8(mark)
+			EmitSwapEvent(g.ID, sender, "place", &cell, &color, nil, ts)
+		}
+		openingAdvance(g, st)
+
+	case "choose":
+		require(step.Op == openingSwap, "wrong phase")
+		require(len(args) == 1, "invalid choice")
+		choice := args[0]
+		switch choice {
+		case "swap":
+			swapSeats(g)
+			saveStateBinary(g)
+		case "stay":
+		default:
+			sdk.Abort("invalid choice")
+		}
+		openingAdvance(g, st)
+		EmitSwapEvent(g.ID, sender, "choose", nil, nil, &choice, ts)
+
+	case "declare":
+		require(step.Op == openingDeclare, "wrong phase")
+		require(len(args) == 1 && args[0] != "", "invalid declare value")
+		n := parseU64Fast(args[0])
+		require(n >= 1 && n <= uint64(step.Offers), "invalid declare value")
+		st.Declared = uint8(n)
+		openingAdvance(g, st)
+		choice := args[0]
+		EmitSwapEvent(g.ID, sender, "declare", nil, nil, &choice, ts)
+
+	case "offer":
+		want := step.Offers
+		if step.Op == openingOffer && want == 0 {
+			want = st.Declared
+		}
+		require(step.Op == openingOffer || (step.Op == openingPlace && step.Offers > 0), "wrong phase")
+		require(len(args) == int(want), "expected "+UInt64ToString(uint64(want))+" offers")
+		// Offers are only checked for being distinct empty cells; symmetric
+		// duplicates are not detected, the picker simply gets fewer real choices.
+		grid, _ := reconstructBoard(g)
+		for _, a := range args {
+			row, col := parseOpeningCell(g, a)
+			require(grid[row][col] == Empty, "cell occupied")
+			cell := uint16(row*cols + col)
+			require(!containsU16(st.Offered, cell), "cell offered twice")
+			st.Offered = append(st.Offered, cell)
+			EmitSwapEvent(g.ID, sender, "offer", &cell, nil, nil, ts)
+		}
+		saveOpening(g.ID, st)
+
+	default:
+		sdk.Abort("invalid swap op")
+	}
+}
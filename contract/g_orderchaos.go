@@ -0,0 +1,33 @@
+package main
+
+//
+// Order and Chaos helpers.
+//
+// Both players may place either symbol. The X seat plays Order and
+// moves first: exactly five of a kind in a row, in either symbol, wins
+// for Order; an overline of six does not. The O seat plays Chaos and wins once the board is full
+// without such a line, so the game never ends in a draw.
+//
+
+// parseOrderChaosSymbol reads the symbol field of an Order and Chaos move.
+func parseOrderChaosSymbol(in *string) Cell {
+	require(*in != "", "missing symbol")
+	sym := Cell(parseU8Fast(nextField(in)))
+	require(sym == X || sym == O, "invalid symbol")
+	return sym
+}
+
+// finalizeOrderChaos checks the line through the new stone for Order
+// and hands the game to Chaos when the board filled up.
+func finalizeOrderChaos(g *Game, grid [][]Cell, row, col int, mvCount uint64, ts uint64) bool {
+	winLen, exact := winLengthFor(g)
+	if checkLine(g, grid, row, col, winLen, exact) {
+		declareWinner(g, X, ts)
+		return true
+	}
+	if boardFull(g, grid, mvCount) {
+		declareWinner(g, O, ts)
+		return true
+	}
+	return false
+}
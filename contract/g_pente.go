@@ -0,0 +1,69 @@
+package main
+
+//
+// Pente capture helpers.
+//
+// Placing a stone so that it flanks exactly two opposing stones in a line
+// removes that pair. Captured cells are written into the move record so
+// the board and capture counts can be rebuilt by replaying the moves.
+//
+
+// penteCaptureWin is the number of captured pairs that wins the game.
+const penteCaptureWin = 5
+
+// penteDirs are all eight directions a capture can run from the new stone.
+var penteDirs = [8][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {-1, -1}, {1, -1}, {-1, 1}}
+
+// penteCapture removes every pair flanked by the stone just placed at
+// (row,col) and returns the removed cells in a stable direction order.
+func penteCapture(grid [][]Cell, row, col int, mark Cell) [][2]int {
+	rows, cols := len(grid), len(grid[0])
+	opp := X
+	if mark == X {
+		opp = O
+	}
+
+	var captured [][2]int
+	for _, d := range penteDirs {
+		r3, c3 := row+3*d[0], col+3*d[1]
+		if r3 < 0 || r3 >= rows || c3 < 0 || c3 >= cols {
+			continue
+		}
+		r1, c1 := row+d[0], col+d[1]
+		r2, c2 := row+2*d[0], col+2*d[1]
+		if grid[r1][c1] == opp && grid[r2][c2] == opp && grid[r3][c3] == mark {
+			grid[r1][c1] = Empty
+			grid[r2][c2] = Empty
+			captured = append(captured, [2]int{r1, c1}, [2]int{r2, c2})
+		}
+	}
+	return captured
+}
+
+// addCaptures credits captured stones to the side that made the move.
+func (g *Game) addCaptures(mark Cell, stones uint16) {
+	if mark == X {
+		g.CapturesX += stones
+	} else {
+		g.CapturesO += stones
+	}
+}
+
+// capturesOf returns how many pairs the given side has captured so far.
+func (g *Game) capturesOf(mark Cell) uint16 {
+	if mark == X {
+		return g.CapturesX / 2
+	}
+	return g.CapturesO / 2
+}
+
+// capturedCellsOf replays the move log and collects every cell that was
+// captured so far, in the order the captures happened.
+func capturedCellsOf(g *Game) [][2]int {
+	var out [][2]int
+	count := readMoveCount(g.ID)
+	for i := uint64(1); i <= count; i++ {
+		out = append(out, decodeMoveCaptures(g, readMoveRaw(g.ID, i))...)
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/binary"
+
+	"okinoko-in_a_row/sdk"
+)
+
+//
+// Pie rule.
+//
+// With the pie rule on, the player at O may answer X's first move by
+// taking it over instead of replying: the seats are swapped, the stone
+// stays, and the former X player continues as O. This makes opening with
+// a too strong move pointless and works without any wager, unlike first
+// move purchase. The swap time is stored so the timeout clock restarts.
+//
+
+// pieKey builds the storage key that marks a used pie swap.
+func pieKey(id uint64) string { return "g_" + UInt64ToString(id) + "_pie" }
+
+// supportsPie reports whether the pie rule may be enabled for a game type.
+func supportsPie(gt GameType) bool {
+	return gt == TicTacToe5 || gt == Squava || gt == ConnectFour
+}
+
+// pieSwappedAt returns when the pie swap happened, or 0 if it didn't.
+func pieSwappedAt(id uint64) uint64 {
+	ptr := sdk.StateGetObject(pieKey(id))
+	if ptr == nil || len(*ptr) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64([]byte(*ptr))
+}
+
+// pieAvailable reports whether O may still swap: right after the
+// first move, and only once.
+func pieAvailable(g *Game, mvCount uint64) bool {
+	return g.Pie && g.Status == InProgress && mvCount == 1 && pieSwappedAt(g.ID) == 0
+}
+
+// pieSwapMove handles "choose|swap" through g_swap for pie games.
+func pieSwapMove(g *Game, sender, op, in string, ts uint64) {
+	require(op == "choose" && in == "swap", "pie rule only allows choose|swap")
+	require(pieAvailable(g, readMoveCount(g.ID)), "pie swap not available")
+	require(sender == *g.PlayerO, "only O can swap")
+
+	swapSeats(g)
+	saveStateBinary(g)
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], ts)
+	sdk.StateSetObject(pieKey(g.ID), string(buf[:]))
+	EmitPieSwap(g.ID, sender, ts)
+}
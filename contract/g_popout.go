@@ -0,0 +1,72 @@
+package main
+
+//
+// Connect Four PopOut helpers.
+//
+// Besides dropping a disc, a player may pop one of their own discs out
+// of the bottom row; everything above slides down one row. Pops are
+// stored as regular move records with the pop bit set on the mark, so
+// replaying the log shifts the column again. Pops free cells again, so
+// a move cap ends endless cycling in a draw.
+//
+
+// popOutMaxMoves is the number of moves (drops and pops, both sides
+// together) after which a PopOut game without a winner ends in a draw.
+const popOutMaxMoves = 200
+
+// popOutCapReached reports whether the game hit the move cap.
+func popOutCapReached(g *Game, mvCount uint64) bool {
+	return g.Type == PopOut && mvCount >= popOutMaxMoves
+}
+
+// popColumn removes the bottom disc of a column and lets the discs
+// above it fall down one row.
+func popColumn(grid [][]Cell, col int) {
+	for r := len(grid) - 1; r > 0; r-- {
+		grid[r][col] = grid[r-1][col]
+	}
+	grid[0][col] = Empty
+}
+
+// canPop reports whether the side has a disc in the bottom row.
+func canPop(grid [][]Cell, mark Cell) bool {
+	for _, c := range grid[len(grid)-1] {
+		if c == mark {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPop validates and performs a pop of the player's own bottom disc.
+func applyPop(grid [][]Cell, col int, mark Cell) {
+	require(col >= 0 && col < len(grid[0]), "invalid move")
+	require(grid[len(grid)-1][col] == mark, "can only pop own disc")
+	popColumn(grid, col)
+}
+
+// finalizePop checks every disc that moved in the popped column.
+// A pop can complete lines for both sides at once; the player who
+// popped wins in that case. mvCount includes the pop.
+func finalizePop(g *Game, grid [][]Cell, col int, mark Cell, mvCount uint64, ts uint64) bool {
+	winLen, exact := winLengthFor(g)
+	var won [3]bool // indexed by Cell
+	for r := range grid {
+		if c := grid[r][col]; c != Empty && checkPatternGrid(grid, r, col, winLen, exact) {
+			won[c] = true
+		}
+	}
+	switch {
+	case won[mark]:
+		declareWinner(g, mark, ts)
+	case won[X]:
+		declareWinner(g, X, ts)
+	case won[O]:
+		declareWinner(g, O, ts)
+	case popOutCapReached(g, mvCount):
+		declareDraw(g, ts)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package main
+
+//
+// Renju forbidden-move detection.
+//
+// Black (X) may not play a double-three, a double-four or an overline
+// unless the same stone also completes exactly five. White has no
+// restrictions. Whether a forbidden move is rejected or loses the game
+// is a per-game setting (Game.ForbiddenLoses).
+//
+
+// renjuDirs are the four line directions checked around a stone.
+var renjuDirs = [4][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+
+// renjuForbidden reports whether the black stone just placed at (row,col)
+// forms a forbidden shape. A move that makes exactly five is never forbidden.
+//
+// Note: open threes are counted without recursing into whether the point
+// that would turn them into a straight four is itself forbidden. This keeps
+// the check bounded and cheap; the rare shapes it misjudges are accepted.
+func renjuForbidden(grid [][]Cell, row, col int) bool {
+	if checkPatternGrid(grid, row, col, 5, true) {
+		return false
+	}
+
+	fours, threes := 0, 0
+	for _, d := range renjuDirs {
+		back, fwd := renjuRun(grid, row, col, d[0], d[1])
+		if 1+back+fwd >= 6 {
+			return true // overline
+		}
+		fours += renjuFoursInLine(grid, row, col, d[0], d[1])
+		if renjuOpenThreeInLine(grid, row, col, d[0], d[1]) {
+			threes++
+		}
+	}
+	return fours >= 2 || threes >= 2
+}
+
+// renjuRun counts contiguous black stones from (row,col) backward and
+// forward along (dr,dc), not counting the stone itself.
+func renjuRun(grid [][]Cell, row, col, dr, dc int) (back, fwd int) {
+	for renjuAt(grid, row+(fwd+1)*dr, col+(fwd+1)*dc) == X {
+		fwd++
+	}
+	for renjuAt(grid, row-(back+1)*dr, col-(back+1)*dc) == X {
+		back++
+	}
+	return
+}
+
+// renjuAt returns the cell at (r,c), or a non-empty, non-black sentinel
+// when the position is off the board so edges act like walls.
+func renjuAt(grid [][]Cell, r, c int) Cell {
+	if r < 0 || r >= len(grid) || c < 0 || c >= len(grid[0]) {
+		return O
+	}
+	return grid[r][c]
+}
+
+// renjuFoursInLine counts the fours through (row,col) on one line.
+// A four is a shape where one more black stone makes exactly five.
+// A straight four (two completion points around four in a row) counts once.
+func renjuFoursInLine(grid [][]Cell, row, col, dr, dc int) int {
+	var points []int
+	for k := -4; k <= 4; k++ {
+		r, c := row+k*dr, col+k*dc
+		if k == 0 || renjuAt(grid, r, c) != Empty {
+			continue
+		}
+		grid[r][c] = X
+		back, fwd := renjuRun(grid, row, col, dr, dc)
+		grid[r][c] = Empty
+		if 1+back+fwd == 5 && k >= -back && k <= fwd {
+			points = append(points, k)
+		}
+	}
+	if len(points) == 2 && points[1]-points[0] == 5 {
+		return 1 // straight four
+	}
+	return len(points)
+}
+
+// renjuOpenThreeInLine reports whether (row,col) is part of an open three
+// on one line: a shape where one more black stone makes a straight four,
+// i.e. four in a row with both ends free to complete exactly five.
+func renjuOpenThreeInLine(grid [][]Cell, row, col, dr, dc int) bool {
+	for k := -3; k <= 3; k++ {
+		r, c := row+k*dr, col+k*dc
+		if k == 0 || renjuAt(grid, r, c) != Empty {
+			continue
+		}
+		grid[r][c] = X
+		back, fwd := renjuRun(grid, row, col, dr, dc)
+		open := 1+back+fwd == 4 && k >= -back && k <= fwd &&
+			renjuStraightEnd(grid, row, col, dr, dc, fwd+1) &&
+			renjuStraightEnd(grid, row, col, -dr, -dc, back+1)
+		grid[r][c] = Empty
+		if open {
+			return true
+		}
+	}
+	return false
+}
+
+// renjuStraightEnd checks the end of a four at distance dist from (row,col):
+// the end point must be empty and the point after it must not be black,
+// so filling the end gives exactly five rather than an overline.
+func renjuStraightEnd(grid [][]Cell, row, col, dr, dc, dist int) bool {
+	if renjuAt(grid, row+dist*dr, col+dist*dc) != Empty {
+		return false
+	}
+	return renjuAt(grid, row+(dist+1)*dr, col+(dist+1)*dc) != X
+}
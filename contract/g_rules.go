@@ -0,0 +1,115 @@
+package main
+
+//
+// Line rule layer.
+//
+// Completing a line either wins or loses for the player who made it.
+// Squava's "lose on 3" and the misère flag are both expressed as line
+// rules here, so finalizeIfWinOrDraw needs no per-game branches for them.
+// Games may add winning shapes besides straight lines (teeko squares).
+//
+
+// lineRule says what completing a line of Len stones does to the mover.
+type lineRule struct {
+	Len   int
+	Exact bool
+	Loses bool
+}
+
+// lineRules lists the line rules of a game in the order they are checked.
+// The win line comes first so a longer winning line beats a shorter
+// losing one (squava). Misère turns the win line into a losing one.
+func lineRules(g *Game) []lineRule {
+	winLen, exact := winLengthFor(g)
+	rules := []lineRule{{Len: winLen, Exact: exact, Loses: g.Rules.Misere}}
+	if g.Rules.LoseLen > 0 {
+		rules = append(rules, lineRule{Len: int(g.Rules.LoseLen), Exact: exact, Loses: true})
+	}
+	return rules
+}
+
+// lineOutcome applies the line rules and winning shapes to the stone just
+// placed at (row,col) and returns the side that wins because of it, or
+// Empty if no rule fired. Shapes count like the win line.
+func lineOutcome(g *Game, grid [][]Cell, row, col int, mark Cell) Cell {
+	for _, lr := range lineRules(g) {
+		if checkLine(g, grid, row, col, lr.Len, lr.Exact) {
+			if lr.Loses {
+				return otherSide(mark)
+			}
+			return mark
+		}
+	}
+	if checkShapes(grid, row, col, winShapes(g)) {
+		if g.Rules.Misere {
+			return otherSide(mark)
+		}
+		return mark
+	}
+	return Empty
+}
+
+// shape is a winning pattern given as cell offsets (row, col).
+type shape [][2]int
+
+// teekoSquares are the corners of every square that fits a 5x5 board,
+// from the plain 2x2 block up to the four board corners.
+var teekoSquares = []shape{
+	{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+	{{0, 0}, {0, 2}, {2, 0}, {2, 2}},
+	{{0, 0}, {0, 3}, {3, 0}, {3, 3}},
+	{{0, 0}, {0, 4}, {4, 0}, {4, 4}},
+}
+
+// winShapes lists the winning patterns of a game besides straight lines.
+func winShapes(g *Game) []shape {
+	if g.Type == Teeko {
+		return teekoSquares
+	}
+	return nil
+}
+
+// checkShapes reports whether the stone at (row,col) completes one of the
+// shapes. Every cell of a shape is tried as the new stone's position, so
+// only placements that include the new stone are checked.
+func checkShapes(grid [][]Cell, row, col int, shapes []shape) bool {
+	mark := grid[row][col]
+	if mark == Empty {
+		return false
+	}
+	rows, cols := len(grid), len(grid[0])
+	for _, sh := range shapes {
+		for _, anchor := range sh {
+			r0, c0 := row-anchor[0], col-anchor[1]
+			all := true
+			for _, o := range sh {
+				r, c := r0+o[0], c0+o[1]
+				if r < 0 || r >= rows || c < 0 || c >= cols || grid[r][c] != mark {
+					all = false
+					break
+				}
+			}
+			if all {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// otherSide returns the opposing mark.
+func otherSide(mark Cell) Cell {
+	if mark == X {
+		return O
+	}
+	return X
+}
+
+// supportsMisere reports whether the misère flag may be set at create.
+func supportsMisere(gt GameType) bool {
+	switch gt {
+	case TicTacToe, TicTacToe5, ConnectFour, Custom:
+		return true
+	}
+	return false
+}
@@ -13,10 +13,16 @@ import (
 // swap2Key builds the storage key for a game's swap2 state.
 func swap2Key(id uint64) string { return "g_" + UInt64ToString(id) + "_swap2" }
 
+// usesSwap2 reports whether a game type opens with the swap2 protocol.
+// That is every gomoku flavour, renju included.
+func usesSwap2(gt GameType) bool {
+	return gt == Gomoku || gt == GomokuFreestyle || gt == Renju
+}
+
 // initSwap2IfGomokuBinary creates a fresh swap2 state for Gomoku only.
 // Other game modes skip this logic entirely.
 func initSwap2IfGomokuBinary(g *Game) {
-	if g.Type != Gomoku && g.Type != GomokuFreestyle {
+	if !usesSwap2(g.Type) {
 		return
 	}
 	roleX := uint8(1)
@@ -88,7 +94,7 @@ func swapPlaceOpening(g *Game, st *swap2StateBinary, sender string, a1, a2, a3 s
 	col := int(parseU8Fast(a2))
 	cell := Cell(parseU8Fast(a3))
 
-	rows, cols := boardDimensions(Gomoku)
+	rows, cols := boardDimensions(g)
 	require(row >= 0 && row < rows && col >= 0 && col < cols, "invalid coord")
 	require(cell == X || cell == O, "invalid cell")
 
@@ -97,7 +103,7 @@ func swapPlaceOpening(g *Game, st *swap2StateBinary, sender string, a1, a2, a3 s
 
 	ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
 	newMv := mv + 1
-	appendMoveBinary(g.ID, newMv, row, col, cell, ts, g.CreatedAt)
+	appendMoveBinary(g, newMv, row, col, cell, ts)
 	writeMoveCount(g.ID, newMv)
 	setCellGrid(grid, row, col, cell)
 
@@ -126,7 +132,7 @@ func swapAddExtra(g *Game, st *swap2StateBinary, sender string, a1, a2, a3 strin
 	col := int(parseU8Fast(a2))
 	cell := Cell(parseU8Fast(a3))
 
-	rows, cols := boardDimensions(Gomoku)
+	rows, cols := boardDimensions(g)
 	require(row >= 0 && row < rows && col >= 0 && col < cols, "invalid coord")
 	require(cell == X || cell == O, "invalid cell")
 
@@ -135,7 +141,7 @@ func swapAddExtra(g *Game, st *swap2StateBinary, sender string, a1, a2, a3 strin
 
 	ts := parseISO8601ToUnix(*sdk.GetEnvKey("block.timestamp"))
 	newMv := mv + 1
-	appendMoveBinary(g.ID, newMv, row, col, cell, ts, g.CreatedAt)
+	appendMoveBinary(g, newMv, row, col, cell, ts)
 	writeMoveCount(g.ID, newMv)
 	setCellGrid(grid, row, col, cell)
 
@@ -164,9 +170,7 @@ func swapFinalColor(g *Game, st *swap2StateBinary, sender string, a1 string) {
 	require(ch == 1 || ch == 2, "invalid color")
 
 	if ch == 2 {
-		tmp := g.PlayerX
-		g.PlayerX = *g.PlayerO
-		*g.PlayerO = tmp
+		swapSeats(g)
 	}
 
 	st.Phase = swap2PhaseNone
@@ -181,9 +185,7 @@ func swapChooseSide(g *Game, st *swap2StateBinary, sender string, choice string)
 
 	switch choice {
 	case "swap":
-		tmp := g.PlayerX
-		g.PlayerX = *g.PlayerO
-		*g.PlayerO = tmp
+		swapSeats(g)
 
 	case "stay":
 		// no change
@@ -205,6 +207,15 @@ func swapChooseSide(g *Game, st *swap2StateBinary, sender string, choice string)
 
 }
 
+// swapSeats exchanges the players at X and O. Callers persist the
+// roles with saveStateBinary. Also used by the other gomoku openings
+// and the pie rule.
+func swapSeats(g *Game) {
+	tmp := g.PlayerX
+	g.PlayerX = *g.PlayerO
+	*g.PlayerO = tmp
+}
+
 // setNextActor updates which logical role acts next.
 // 1 = X side, 2 = O side.
 func setNextActor(st *swap2StateBinary, g *Game, role uint8) {
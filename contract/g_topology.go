@@ -0,0 +1,196 @@
+package main
+
+import "okinoko-in_a_row/sdk"
+
+//
+// Board topology modifiers.
+//
+// Wrap-around lets lines continue across the board edges (torus: both
+// axes, cylinder: columns only). Gravity makes pieces fall towards one
+// of the four edges instead of staying where they were put. Both are
+// chosen at g_create and combine with most game types.
+//
+
+// Wrap modes.
+const (
+	wrapNone     uint8 = 0
+	wrapTorus    uint8 = 1 // rows and columns wrap
+	wrapCylinder uint8 = 2 // only columns wrap (left edge meets right edge)
+)
+
+// Fall directions used when Ruleset.Gravity is set.
+const (
+	fallDown  uint8 = 0
+	fallUp    uint8 = 1
+	fallLeft  uint8 = 2
+	fallRight uint8 = 3
+)
+
+var wrapNames = [...]string{wrapNone: "none", wrapTorus: "torus", wrapCylinder: "cylinder"}
+var fallNames = [...]string{fallDown: "down", fallUp: "up", fallLeft: "left", fallRight: "right"}
+
+// supportsTopology reports whether wrap and fall options may be used.
+// Games whose rules depend on the board edges or a fixed geometry
+// (sub-boards, cubes, pops, captures, renju shapes, moving pieces) are left out.
+func supportsTopology(gt GameType) bool {
+	switch gt {
+	case UltimateTicTacToe, Notakto, Qubic, PopOut, Renju, Pente, Tapatan, Teeko:
+		return false
+	}
+	return true
+}
+
+// applyTopologyOption sets a wrap or fall option on the ruleset.
+func applyTopologyOption(g *Game, key, val string) {
+	require(supportsTopology(g.Type), "option "+key+" not available for this game")
+	switch key {
+	case "wrap":
+		g.Rules.Wrap = nameIndex(wrapNames[:], val, "invalid wrap value")
+	case "fall":
+		// swap2 opening stones are placed freely, which gravity can't follow
+		require(!usesSwap2(g.Type), "option fall not available for this game")
+		g.Rules.Gravity = true
+		g.Rules.FallDir = nameIndex(fallNames[:], val, "invalid fall value")
+	}
+}
+
+// nameIndex returns the position of val in names or aborts with msg.
+func nameIndex(names []string, val, msg string) uint8 {
+	for i, n := range names {
+		if n == val {
+			return uint8(i)
+		}
+	}
+	sdk.Abort(msg)
+	return 0
+}
+
+// topologyFields lists non-default topology settings as key/value pairs.
+func topologyFields(g *Game) []string {
+	var kv []string
+	if g.Rules.Wrap != wrapNone {
+		kv = append(kv, "wrap", wrapNames[g.Rules.Wrap])
+	}
+	if g.Rules.Gravity && (g.Rules.FallDir != fallDown || !presetRules(g.Type).Gravity) {
+		kv = append(kv, "fall", fallNames[g.Rules.FallDir])
+	}
+	return kv
+}
+
+// encodeTopology packs wrap, gravity and fall direction into 3 bytes.
+func encodeTopology(rs Ruleset) []byte {
+	return []byte{rs.Wrap, boolByte(rs.Gravity), rs.FallDir}
+}
+
+// applyTopology is the inverse of encodeTopology.
+func applyTopology(rs *Ruleset, b []byte) {
+	require(len(b) == 3 && int(b[0]) < len(wrapNames) && int(b[2]) < len(fallNames), "invalid topology")
+	rs.Wrap, rs.Gravity, rs.FallDir = b[0], b[1] == 1, b[2]
+}
+
+// hasTopology reports whether the ruleset differs from the flat,
+// preset-gravity board, so the topology has to be stored.
+func hasTopology(g *Game) bool {
+	return g.Rules.Wrap != wrapNone || g.Rules.FallDir != fallDown || g.Rules.Gravity != presetRules(g.Type).Gravity
+}
+
+// fallGrid lets a piece fall towards the game's gravity edge. Down and up
+// use the column of the move, left and right its row. The piece enters
+// from the opposite edge and stops on the first occupied or blocked cell.
+// Returns the cell it lands on, or -1,-1 when that line is full.
+func fallGrid(grid [][]Cell, row, col int, dir uint8) (int, int) {
+	rows, cols := len(grid), len(grid[0])
+	var r, c, dr, dc int
+	switch dir {
+	case fallUp:
+		r, c, dr = rows-1, col, -1
+	case fallLeft:
+		r, c, dc = row, cols-1, -1
+	case fallRight:
+		r, c, dc = row, 0, 1
+	default:
+		r, c, dr = 0, col, 1
+	}
+	if grid[r][c] != Empty {
+		return -1, -1
+	}
+	for {
+		nr, nc := r+dr, c+dc
+		if nr < 0 || nr >= rows || nc < 0 || nc >= cols || grid[nr][nc] != Empty {
+			return r, c
+		}
+		r, c = nr, nc
+	}
+}
+
+// canDrop reports whether a gravity board still takes a piece in any
+// line. Cells behind an obstacle can never be reached, so counting the
+// empty cells isn't enough to tell a full board.
+func canDrop(grid [][]Cell, dir uint8) bool {
+	lines := len(grid[0])
+	if dir == fallLeft || dir == fallRight {
+		lines = len(grid)
+	}
+	for i := 0; i < lines; i++ {
+		// fallGrid only reads the index along the entry edge
+		if r, _ := fallGrid(grid, i, i, dir); r >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPatternWrap is checkPatternGrid for boards whose rows and/or
+// columns wrap around. A run stops at the first other cell; a line that
+// wraps all the way around counts every cell of the cycle once.
+func checkPatternWrap(grid [][]Cell, row, col, winLen int, exactLen, wrapRows, wrapCols bool) bool {
+	rows, cols := len(grid), len(grid[0])
+	mark := grid[row][col]
+	if mark == Empty {
+		return false
+	}
+
+	// step moves one cell along (dr,dc), wrapping where allowed
+	step := func(r, c, dr, dc int) (int, int, bool) {
+		r, c = r+dr, c+dc
+		if wrapRows {
+			r = (r + rows) % rows
+		}
+		if wrapCols {
+			c = (c + cols) % cols
+		}
+		return r, c, r >= 0 && r < rows && c >= 0 && c < cols
+	}
+
+	dirs := [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+	for _, d := range dirs {
+		count := 1
+		cycle := false
+		r, c := row, col
+		for {
+			var ok bool
+			if r, c, ok = step(r, c, d[0], d[1]); !ok || grid[r][c] != mark {
+				break
+			}
+			if r == row && c == col {
+				cycle = true
+				break
+			}
+			count++
+		}
+		if !cycle {
+			r, c = row, col
+			for {
+				var ok bool
+				if r, c, ok = step(r, c, -d[0], -d[1]); !ok || grid[r][c] != mark {
+					break
+				}
+				count++
+			}
+		}
+		if count == winLen || (!exactLen && count > winLen) {
+			return true
+		}
+	}
+	return false
+}
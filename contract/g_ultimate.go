@@ -0,0 +1,66 @@
+package main
+
+//
+// Ultimate Tic-Tac-Toe helpers.
+//
+// The 9x9 grid is made of nine 3x3 sub-boards. The cell a player picks
+// inside a sub-board sends the opponent to the matching sub-board next.
+// A sub-board is closed once it is won or full; being sent to a closed
+// sub-board lets the player choose any open one. Three won sub-boards
+// in a row win the game.
+//
+
+// ultimateMetaGrid returns the state of all nine sub-boards as a 3x3 grid.
+func ultimateMetaGrid(grid [][]Cell) [][]Cell {
+	meta := make([][]Cell, 3)
+	for br := 0; br < 3; br++ {
+		meta[br] = make([]Cell, 3)
+		for bc := 0; bc < 3; bc++ {
+			meta[br][bc] = subBoardState(grid, br, bc)
+		}
+	}
+	return meta
+}
+
+// ultimateActiveBoard returns the sub-board (0-8) the next move must go to,
+// or -1 when the player may choose any open sub-board.
+func ultimateActiveBoard(g *Game, grid [][]Cell) int {
+	count := readMoveCount(g.ID)
+	if count == 0 {
+		return -1
+	}
+	r, c, _, _ := decodeMoveBinary(g, readMoveRaw(g.ID, count))
+	br, bc := r%3, c%3
+	if subBoardState(grid, br, bc) != subBoardOpen {
+		return -1
+	}
+	return br*3 + bc
+}
+
+// ultimateRequireTarget aborts unless (row,col) lies in an open sub-board
+// and, if one is forced, in the active sub-board.
+func ultimateRequireTarget(g *Game, grid [][]Cell, row, col int) {
+	require(subBoardState(grid, row/3, col/3) == subBoardOpen, "sub-board closed")
+	active := ultimateActiveBoard(g, grid)
+	require(active < 0 || active == (row/3)*3+col/3, "must play in sub-board "+UInt64ToString(uint64(active)))
+}
+
+// finalizeUltimate checks the meta board after a move: three won
+// sub-boards in a row win, no open sub-board left is a draw.
+func finalizeUltimate(g *Game, grid [][]Cell, row, col int, mark Cell, ts uint64) bool {
+	meta := ultimateMetaGrid(grid)
+	br, bc := row/3, col/3
+	if meta[br][bc] == mark && checkPatternGrid(meta, br, bc, 3, false) {
+		declareWinner(g, mark, ts)
+		return true
+	}
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			if meta[r][c] == subBoardOpen {
+				return false
+			}
+		}
+	}
+	declareDraw(g, ts)
+	return true
+}
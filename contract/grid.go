@@ -13,28 +13,45 @@ import (
 
 // reconstructBoard rebuilds the current board state from stored moves.
 // Returns the grid and total move count. Cells are assigned in order
-// (odd=X, even=O) based on stored move sequence.
+// (odd=X, even=O) based on stored move sequence, on top of the
+// blocked cells of obstacle boards. Captured stones are
+// removed again and tallied on g.CapturesX / g.CapturesO, popped
+// discs (PopOut) shift their column down and slides (movement phase)
+// vacate their from-cell.
 func reconstructBoard(g *Game) ([][]Cell, uint64) {
-	rows, cols := boardDimensions(g.Type)
+	rows, cols := boardDimensions(g)
 	grid := make([][]Cell, rows)
 	for i := 0; i < rows; i++ {
 		grid[i] = make([]Cell, cols)
 	}
 
+	applyBlockedMask(g, grid)
 	count := readMoveCount(g.ID)
-	createdAt := g.CreatedAt
 
+	g.CapturesX, g.CapturesO = 0, 0
 	for i := uint64(1); i <= count; i++ {
-		r, c, ch, _ := readMoveBinary(g.ID, i, createdAt)
+		data := readMoveRaw(g.ID, i)
+		r, c, ch, _ := decodeMoveBinary(g, data)
+		if isPopMove(data) {
+			popColumn(grid, c)
+			continue
+		}
 		grid[r][c] = ch
+		slide := isSlideMove(data)
+		for _, rc := range decodeMoveCaptures(g, data) {
+			grid[rc[0]][rc[1]] = Empty
+			if !slide {
+				g.addCaptures(ch, 1)
+			}
+		}
 	}
 
 	return grid, count
 }
 
 // asciiFromGrid flattens a board to a compact ASCII string.
-// Each cell becomes '0','1','2' which makes debugging simpler
-// and keeps things tiny on-chain.
+// Each cell becomes '0','1','2' (or '3' for a blocked cell) which
+// makes debugging simpler and keeps things tiny on-chain.
 func asciiFromGrid(grid [][]Cell) string {
 	rows := len(grid)
 	if rows == 0 {
@@ -53,6 +70,59 @@ func asciiFromGrid(grid [][]Cell) string {
 	return string(out)
 }
 
+// cellIndices converts row/col pairs into flat row*cols+col indices.
+func cellIndices(cells [][2]int, cols int) []uint16 {
+	out := make([]uint16, 0, len(cells))
+	for _, rc := range cells {
+		out = append(out, uint16(rc[0]*cols+rc[1]))
+	}
+	return out
+}
+
+// appendCellList writes cells as comma separated flat indices.
+func appendCellList(dst []byte, cells [][2]int, cols int) []byte {
+	return appendU16List(dst, cellIndices(cells, cols))
+}
+
+// Sub-board states: open, won by X, won by O, or full without a line.
+const (
+	subBoardOpen Cell = Empty
+	subBoardFull Cell = 3
+)
+
+// subBoardGrid copies one 3x3 sub-board out of a grid made of 3x3 blocks
+// (ultimate, notakto), so the regular line checker can run on it.
+func subBoardGrid(grid [][]Cell, br, bc int) [][]Cell {
+	sub := make([][]Cell, 3)
+	for r := 0; r < 3; r++ {
+		sub[r] = make([]Cell, 3)
+		copy(sub[r], grid[br*3+r][bc*3:bc*3+3])
+	}
+	return sub
+}
+
+// subBoardState returns who made a line on a sub-board, subBoardFull if
+// it is full without a line, or subBoardOpen while it can still be played.
+func subBoardState(grid [][]Cell, br, bc int) Cell {
+	sub := subBoardGrid(grid, br, bc)
+	full := true
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			if sub[r][c] == Empty {
+				full = false
+				continue
+			}
+			if checkPatternGrid(sub, r, c, 3, false) {
+				return sub[r][c]
+			}
+		}
+	}
+	if full {
+		return subBoardFull
+	}
+	return subBoardOpen
+}
+
 // getCellGrid returns the mark at (r,c).
 func getCellGrid(grid [][]Cell, r, c int) Cell {
 	return grid[r][c]
@@ -63,20 +133,6 @@ func setCellGrid(grid [][]Cell, r, c int, v Cell) {
 	grid[r][c] = v
 }
 
-// dropDiscGrid simulates gravity for Connect-Four style boards.
-// Returns the placed row index or -1 if the column is full.
-// Caller is expected to overwrite the provisional mark.
-func dropDiscGrid(grid [][]Cell, col int) int {
-	rows := len(grid)
-	for r := rows - 1; r >= 0; r-- {
-		if grid[r][col] == Empty {
-			grid[r][col] = X // temporary, replaced by caller
-			return r
-		}
-	}
-	return -1
-}
-
 // checkPatternGrid tests if the newly placed stone at (row,col)
 // forms a winning line. Handles both >=N rules and exact-N
 // (gomoku style) where longer lines don't count.
@@ -134,24 +190,168 @@ func checkPatternGrid(grid [][]Cell, row, col, winLen int, exactLen bool) bool {
 	return false
 }
 
-// boardDimensions returns rows and cols for each supported ruleset.
+// boardDimensions returns rows and cols of the game's ruleset.
 // The values here define how we lay out internal grids and move checks.
-func boardDimensions(gt GameType) (int, int) {
+// Layered (3D) boards are stacked layer by layer, so the grid has
+// layers*rows rows and flat cell indices stay row-major.
+func boardDimensions(g *Game) (int, int) {
+	if g.Rules.Layers > 1 {
+		return int(g.Rules.Layers) * int(g.Rules.Rows), int(g.Rules.Cols)
+	}
+	return int(g.Rules.Rows), int(g.Rules.Cols)
+}
+
+// boardShape returns the size of every board axis, outermost first:
+// {rows, cols} for flat boards, {layers, rows, cols} for cubes.
+// Moves carry one coordinate per axis.
+func boardShape(g *Game) []int {
+	if g.Rules.Layers > 1 {
+		return []int{int(g.Rules.Layers), int(g.Rules.Rows), int(g.Rules.Cols)}
+	}
+	return []int{int(g.Rules.Rows), int(g.Rules.Cols)}
+}
+
+// shapeToGrid maps coordinates of an N-dimensional board onto the
+// stacked 2D grid. The last axis is the column, all others fold into the row.
+func shapeToGrid(shape, pos []int) (row, col int) {
+	n := len(shape)
+	for k := 0; k < n-1; k++ {
+		row = row*shape[k] + pos[k]
+	}
+	return row, pos[n-1]
+}
+
+// checkLine tests if the stone at (row,col) completes a line of winLen
+// on the game's board, using the N-dimensional check for cubes and the
+// wrapping check for torus and cylinder boards.
+func checkLine(g *Game, grid [][]Cell, row, col, winLen int, exactLen bool) bool {
+	if g.Rules.Layers > 1 {
+		return checkPatternND(grid, boardShape(g), row, col, winLen, exactLen)
+	}
+	if g.Rules.Wrap != wrapNone {
+		return checkPatternWrap(grid, row, col, winLen, exactLen, g.Rules.Wrap == wrapTorus, true)
+	}
+	return checkPatternGrid(grid, row, col, winLen, exactLen)
+}
+
+// checkPatternND is checkPatternGrid for boards of any dimension stored
+// as a stacked grid. Lines run along every direction with components in
+// {-1,0,1}; each line is visited once by only using directions whose
+// first non-zero component is positive (4 in 2D, 13 in 3D), which covers
+// all 76 lines of a 4x4x4 cube including the space diagonals.
+func checkPatternND(grid [][]Cell, shape []int, row, col, winLen int, exactLen bool) bool {
+	n := len(shape)
+	cols := shape[n-1]
+	mark := grid[row][col]
+	if mark == Empty {
+		return false
+	}
+
+	// unfold the stacked grid position into one coordinate per axis
+	pos := make([]int, n)
+	flat := row*cols + col
+	for k := n - 1; k >= 0; k-- {
+		pos[k] = flat % shape[k]
+		flat /= shape[k]
+	}
+
+	at := func(p []int) Cell {
+		for k, v := range p {
+			if v < 0 || v >= shape[k] {
+				return Empty
+			}
+		}
+		r, c := shapeToGrid(shape, p)
+		return grid[r][c]
+	}
+	walk := func(dir []int, sign int) int {
+		p := make([]int, n)
+		copy(p, pos)
+		count := 0
+		for {
+			for k := range p {
+				p[k] += sign * dir[k]
+			}
+			if at(p) != mark {
+				return count
+			}
+			count++
+		}
+	}
+
+	dir := make([]int, n)
+	total := 1
+	for i := 0; i < n; i++ {
+		total *= 3
+	}
+	for code := 0; code < total; code++ {
+		lead := 0
+		for k, v := 0, code; k < n; k++ {
+			dir[k] = v%3 - 1
+			v /= 3
+			if lead == 0 {
+				lead = dir[k]
+			}
+		}
+		if lead != 1 {
+			continue
+		}
+		count := 1 + walk(dir, 1) + walk(dir, -1)
+		if exactLen {
+			// walks stop at the first non-matching cell, so the count is the full run
+			if count == winLen {
+				return true
+			}
+			continue
+		}
+		if count >= winLen {
+			return true
+		}
+	}
+	return false
+}
+
+// presetRules returns the fixed ruleset of a built-in game type.
+// Custom games start empty and get their rules from create options.
+func presetRules(gt GameType) Ruleset {
 	switch gt {
 	case TicTacToe:
-		return 3, 3
+		return Ruleset{Rows: 3, Cols: 3, WinLen: 3}
 	case TicTacToe5:
-		return 5, 5
+		return Ruleset{Rows: 5, Cols: 5, WinLen: 4}
 	case Squava:
-		return 5, 5
-	case ConnectFour:
-		return 6, 7
+		return Ruleset{Rows: 5, Cols: 5, WinLen: 4, LoseLen: 3}
+	case ConnectFour, PopOut:
+		return Ruleset{Rows: 6, Cols: 7, WinLen: 4, Gravity: true}
 	case Gomoku:
-		return 15, 15
+		return Ruleset{Rows: 15, Cols: 15, WinLen: 5, Exact: true}
 	case GomokuFreestyle:
-		return 15, 15
+		return Ruleset{Rows: 15, Cols: 15, WinLen: 5}
+	case Renju:
+		// black's exact-five rule is handled by the renju check
+		return Ruleset{Rows: 15, Cols: 15, WinLen: 5}
+	case Pente:
+		return Ruleset{Rows: 19, Cols: 19, WinLen: 5}
+	case Connect6:
+		return Ruleset{Rows: 19, Cols: 19, WinLen: 6}
+	case UltimateTicTacToe:
+		// nine 3x3 sub-boards; lines only count inside a sub-board
+		return Ruleset{Rows: 9, Cols: 9, WinLen: 3}
+	case OrderChaos:
+		// six of a kind does not count for Order
+		return Ruleset{Rows: 6, Cols: 6, WinLen: 5, Exact: true}
+	case Notakto:
+		return notaktoRules(notaktoMinBoards)
+	case Tapatan:
+		return Ruleset{Rows: 3, Cols: 3, WinLen: 3, Pieces: 3, Step: stepAlquerque}
+	case Teeko:
+		return Ruleset{Rows: 5, Cols: 5, WinLen: 4, Pieces: 4, Step: stepKing}
+	case Qubic:
+		return Ruleset{Rows: 4, Cols: 4, Layers: 4, WinLen: 4}
+	case Custom:
+		return Ruleset{}
 	default:
 		sdk.Abort("invalid game type")
 	}
-	return 0, 0
+	return Ruleset{}
 }
@@ -4,7 +4,7 @@ package main
 // and simple text events for off-chain indexers.
 //
 // Games like tic-tac-toe, connect-four and gomoku are supported,
-// including the swap2 rule for fair gomoku openings.
+// including swap2 and other fair gomoku openings.
 //
 // Each match stores players, bets (optional), and moves in
 // compact binary form. The logic keeps gas low by splitting static meta data
@@ -15,7 +15,7 @@ package main
 // g_move, g_swap, g_timeout, and g_resign.
 //
 // main is empty here since the wasm host calls into exported entrypoints.
-const gameTimeout = 7 * 24 * 3600 // 7 days
+const gameTimeout = 7 * 24 * 3600 // default move timeout, 7 days
 
 func main() {
 	// placeholder needed for contract verification
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"crypto/sha256"
 	"encoding/binary"
 	"okinoko-in_a_row/sdk"
 	"strconv"
@@ -77,10 +78,78 @@ func saveMetaBinary(g *Game) {
 	binary.BigEndian.PutUint64(tsBuf[:], g.CreatedAt)
 	out = append(out, tsBuf[:]...)
 
+	// 9. Extensions (tag + len + data), only written when set
+	if g.ForbiddenLoses {
+		out = appendMetaExt(out, metaExtForbiddenLoses, []byte{1})
+	}
+	if g.Type == Custom || g.Type == Notakto {
+		out = appendMetaExt(out, metaExtRuleset, encodeRuleset(g.Rules))
+	}
+	if g.Rules.Misere {
+		out = appendMetaExt(out, metaExtMisere, []byte{1})
+	}
+	if g.Rules.Pieces != presetRules(g.Type).Pieces {
+		out = appendMetaExt(out, metaExtPieces, []byte{g.Rules.Pieces})
+	}
+	if hasTopology(g) {
+		out = appendMetaExt(out, metaExtTopology, encodeTopology(g.Rules))
+	}
+	if len(g.Blocked) > 0 || g.BlockCount > 0 {
+		out = appendMetaExt(out, metaExtBlocked, encodeBlocked(g))
+	}
+	if g.Opening != OpeningSwap2 {
+		out = appendMetaExt(out, metaExtOpening, []byte{uint8(g.Opening)})
+	}
+	if g.Pie {
+		out = appendMetaExt(out, metaExtPie, []byte{1})
+	}
+	if g.Auction != auctionNone {
+		out = appendMetaExt(out, metaExtAuction, encodeAuction(g))
+	}
+	if g.CoinFlip != nil {
+		out = appendMetaExt(out, metaExtCoinFlip, g.CoinFlip)
+	}
+	if len(g.Invited) > 0 {
+		out = appendMetaExt(out, metaExtInvites, encodeInvites(g.Invited))
+	}
+	if g.JoinHash != nil {
+		out = appendMetaExt(out, metaExtJoinHash, g.JoinHash)
+	}
+	if g.JoinDeadline > 0 {
+		out = appendMetaExt(out, metaExtJoinDeadline, binary.BigEndian.AppendUint64(nil, g.JoinDeadline))
+	}
+	if g.MoveTimeout > 0 {
+		out = appendMetaExt(out, metaExtMoveTimeout, binary.BigEndian.AppendUint32(nil, g.MoveTimeout))
+	}
+	if g.ClockBank > 0 {
+		out = appendMetaExt(out, metaExtClock, encodeClock(g))
+	}
+	if g.JoinedAt > 0 {
+		out = appendMetaExt(out, metaExtJoinedAt, binary.BigEndian.AppendUint64(nil, g.JoinedAt))
+	}
+
 	// ✅ Save to chain
 	sdk.StateSetObject(gameMetaKey(g.ID), string(out))
 }
 
+// appendMetaExt writes one optional meta extension as tag + 2-byte length + data.
+func appendMetaExt(out []byte, tag uint8, data []byte) []byte {
+	out = append(out, tag)
+	return appendString16(out, string(data))
+}
+
+// encodeRuleset packs a custom ruleset into 6 bytes:
+// rows, cols, win length, exact flag, gravity flag, lose length.
+func encodeRuleset(rs Ruleset) []byte {
+	return []byte{rs.Rows, rs.Cols, rs.WinLen, boolByte(rs.Exact), boolByte(rs.Gravity), rs.LoseLen}
+}
+
+// decodeRuleset is the inverse of encodeRuleset.
+func decodeRuleset(b []byte) Ruleset {
+	require(len(b) == 6, "invalid ruleset")
+	return Ruleset{Rows: b[0], Cols: b[1], WinLen: b[2], Exact: b[3] == 1, Gravity: b[4] == 1, LoseLen: b[5]}
+}
+
 // loadMetaBinary reads the immutable game metadata from storage.
 // It does not touch dynamic values like turn or winner; caller must
 // layer state / moves on top afterwards.
@@ -140,6 +209,66 @@ func loadMetaBinary(id uint64) *Game {
 	// 8. CreatedAt
 	createdAt := r.u64()
 
+	// 9. Extensions (optional, games created before they existed simply end here)
+	var forbiddenLoses, misere, pie bool
+	rules := presetRules(gType)
+	pieces := rules.Pieces
+	var topology, blocked, auction, coinFlip, joinHash, clock []byte
+	var invited []string
+	var joinDeadline, joinedAt uint64
+	var moveTimeout uint32
+	opening := OpeningSwap2
+	for r.i < len(r.b) {
+		tag := r.u8()
+		val := []byte(r.str())
+		switch tag {
+		case metaExtForbiddenLoses:
+			forbiddenLoses = len(val) == 1 && val[0] == 1
+		case metaExtRuleset:
+			rules = decodeRuleset(val)
+		case metaExtMisere:
+			misere = len(val) == 1 && val[0] == 1
+		case metaExtPieces:
+			require(len(val) == 1, "invalid pieces")
+			pieces = val[0]
+		case metaExtTopology:
+			topology = val
+		case metaExtBlocked:
+			blocked = val
+		case metaExtOpening:
+			require(len(val) == 1 && int(val[0]) < len(openingNames), "invalid opening")
+			opening = OpeningRule(val[0])
+		case metaExtPie:
+			pie = len(val) == 1 && val[0] == 1
+		case metaExtAuction:
+			auction = val
+		case metaExtCoinFlip:
+			require(len(val) == sha256.Size, "invalid coinflip")
+			coinFlip = val
+		case metaExtInvites:
+			invited = decodeInvites(val)
+		case metaExtJoinHash:
+			require(len(val) == sha256.Size, "invalid join hash")
+			joinHash = val
+		case metaExtJoinDeadline:
+			require(len(val) == 8, "invalid join deadline")
+			joinDeadline = binary.BigEndian.Uint64(val)
+		case metaExtMoveTimeout:
+			require(len(val) == 4, "invalid move timeout")
+			moveTimeout = binary.BigEndian.Uint32(val)
+		case metaExtClock:
+			clock = val
+		case metaExtJoinedAt:
+			require(len(val) == 8, "invalid join time")
+			joinedAt = binary.BigEndian.Uint64(val)
+		}
+	}
+	if topology != nil {
+		applyTopology(&rules, topology)
+	}
+	rules.Misere = rules.Misere || misere
+	rules.Pieces = pieces
+
 	// ✅ Construct game:
 	g := &Game{
 		ID:             id,
@@ -151,15 +280,34 @@ func loadMetaBinary(id uint64) *Game {
 		GameAsset:      gameAsset,
 		GameBetAmount:  betAmount,
 		FirstMoveCosts: fmc,
+		Rules:          rules,
+		ForbiddenLoses: forbiddenLoses,
+		Opening:        opening,
+		Pie:            pie,
+		CoinFlip:       coinFlip,
+		Invited:        invited,
+		JoinHash:       joinHash,
+		JoinDeadline:   joinDeadline,
+		MoveTimeout:    moveTimeout,
 		CreatedAt:      createdAt,
-		LastMoveAt:     createdAt, // will be overwritten if moves exist
+		JoinedAt:       joinedAt,
+		LastMoveAt:     max(createdAt, joinedAt), // will be overwritten if moves exist
+	}
+
+	if blocked != nil {
+		decodeBlocked(g, blocked)
+	}
+	if auction != nil {
+		decodeAuction(g, auction)
+	}
+	if clock != nil {
+		decodeClock(g, clock)
 	}
 
 	// Now compute LastMoveAt from moves if any
 	count := readMoveCount(id)
 	if count > 0 {
-		_, _, _, ts := readMoveBinary(id, count, createdAt)
-		g.LastMoveAt = ts
+		g.LastMoveAt = readMoveTimestamp(id, count, createdAt)
 	}
 
 	return g
@@ -184,10 +332,28 @@ func loadGame(id uint64) *Game {
 	// ---- Apply last move time from binary moves ----
 	count := readMoveCount(id)
 	if count > 0 {
-		_, _, _, lastTs := readMoveBinary(id, count, g.CreatedAt)
-		g.LastMoveAt = lastTs
+		g.LastMoveAt = readMoveTimestamp(id, count, g.CreatedAt)
 	} else {
-		g.LastMoveAt = g.CreatedAt
+		// the first move is due from the join, not from the lobby's creation
+		g.LastMoveAt = max(g.CreatedAt, g.JoinedAt)
+	}
+	// a settled first-move auction starts the clock for X
+	if g.Auction != auctionNone && count == 0 {
+		if st := loadAuction(id); st != nil && st.Settled > g.LastMoveAt {
+			g.LastMoveAt = st.Settled
+		}
+	}
+	// so does a decided coin flip
+	if g.CoinFlip != nil && count == 0 {
+		if st := loadCoinFlip(id); st != nil && st.Decided > g.LastMoveAt {
+			g.LastMoveAt = st.Decided
+		}
+	}
+	// a pie swap restarts the clock for the player now due
+	if g.Pie && count == 1 {
+		if at := pieSwappedAt(id); at > g.LastMoveAt {
+			g.LastMoveAt = at
+		}
 	}
 
 	return g
@@ -284,15 +450,6 @@ func GetFirstTransferAllow(intents []sdk.Intent) *TransferAllow {
 	return nil
 }
 
-// nextToPlay chooses side by move count parity. X starts,
-// and it flips each turn. Used for timeout calc and sanity checks.
-func nextToPlay(moves uint64) Cell {
-	if moves%2 == 0 {
-		return X
-	}
-	return O
-}
-
 // isPlayer checks if the given address matches one of the seats.
 // O may not exist yet for lobby state, so we guard pointer.
 func isPlayer(g *Game, addr string) bool {
@@ -14,14 +14,42 @@ import "okinoko-in_a_row/sdk"
 type GameType uint8
 
 const (
-	TicTacToe       GameType = 1
-	ConnectFour     GameType = 2
-	Gomoku          GameType = 3
-	TicTacToe5      GameType = 4
-	Squava          GameType = 5
-	GomokuFreestyle GameType = 6
+	TicTacToe         GameType = 1
+	ConnectFour       GameType = 2
+	Gomoku            GameType = 3
+	TicTacToe5        GameType = 4
+	Squava            GameType = 5
+	GomokuFreestyle   GameType = 6
+	Renju             GameType = 7
+	Pente             GameType = 8
+	Connect6          GameType = 9
+	Custom            GameType = 10 // board and line rules chosen at create
+	UltimateTicTacToe GameType = 11
+	Qubic             GameType = 12 // 4x4x4 cube
+	PopOut            GameType = 13 // connect four with pops
+	OrderChaos        GameType = 14 // X seat is Order, O seat is Chaos
+	Notakto           GameType = 15 // both place X, making a line loses
+	Tapatan           GameType = 16 // place 3 pieces, then move them
+	Teeko             GameType = 17 // drop 4 pieces, then move; lines or squares win
 )
 
+// Ruleset describes board geometry and line rules of a match.
+// Built-in game types use fixed presets; Custom games store their own.
+type Ruleset struct {
+	Rows    uint8
+	Cols    uint8
+	Layers  uint8 // stacked 2D layers of a 3D board, 0 = flat
+	WinLen  uint8 // stones in a row needed to win
+	Exact   bool  // longer lines don't count (gomoku rule)
+	Gravity bool  // pieces drop to the lowest free row (connect four)
+	LoseLen uint8 // making this many in a row loses (squava), 0 = off
+	Misere  bool  // making the win line loses instead
+	Pieces  uint8 // pieces per side, then pieces move (tapatan), 0 = unlimited
+	Step    uint8 // which neighbours a moving piece may step to
+	Wrap    uint8 // lines continue across edges (torus, cylinder)
+	FallDir uint8 // edge pieces fall towards when Gravity is set
+}
+
 // Cell is the stone or mark on the grid.
 // We stick to three values only for fast checks.
 type Cell uint8
@@ -30,6 +58,8 @@ const (
 	Empty Cell = 0
 	X     Cell = 1
 	O     Cell = 2
+	// Blocked marks an obstacle cell nobody may play on (obstacle boards).
+	Blocked Cell = 3
 )
 
 // GameStatus tracks high-level life cycle of a match.
@@ -39,6 +69,7 @@ const (
 	WaitingForPlayer GameStatus = 0
 	InProgress       GameStatus = 1
 	Finished         GameStatus = 2
+	Cancelled        GameStatus = 3 // ended without a result, stakes refunded
 )
 
 // Game holds all live match data that isn't raw storage.
@@ -53,11 +84,29 @@ type Game struct {
 	PlayerO        *string
 	Status         GameStatus
 	Winner         *string
-	GameAsset      *sdk.Asset // token for optional bets
-	GameBetAmount  *uint64    // wager amount, if any
-	CreatedAt      uint64     // unix seconds
-	LastMoveAt     uint64     // unix seconds
-	FirstMoveCosts *uint64    // extra fee to buy first move
+	GameAsset      *sdk.Asset  // token for optional bets
+	GameBetAmount  *uint64     // wager amount, if any
+	CreatedAt      uint64      // unix seconds
+	JoinedAt       uint64      // unix seconds the opponent joined, 0 = not yet
+	LastMoveAt     uint64      // unix seconds
+	FirstMoveCosts *uint64     // extra fee to buy first move
+	Rules          Ruleset     // board and line rules, preset or custom
+	ForbiddenLoses bool        // renju: forbidden black move loses instead of being rejected
+	CapturesX      uint16      // pente: stones captured by X, rebuilt from moves
+	CapturesO      uint16      // pente: stones captured by O, rebuilt from moves
+	Blocked        []uint16    // obstacle cells (row*cols+col)
+	BlockCount     uint8       // random obstacles drawn at join, 0 = none
+	Opening        OpeningRule // gomoku opening protocol, swap2 by default
+	Pie            bool        // second player may take over the first move
+	Auction        uint8       // first-move auction mode, 0 = none
+	AuctionWindow  uint32      // auction bid (and reveal) window in seconds
+	CoinFlip       []byte      // creator's coin flip commitment, nil = off
+	Invited        []string    // accounts allowed to join, empty = anyone
+	JoinHash       []byte      // sha256 of the lobby password, nil = open
+	JoinDeadline   uint64      // unix seconds after which the lobby expires, 0 = never
+	MoveTimeout    uint32      // seconds per move before g_timeout, 0 = gameTimeout
+	ClockBank      uint32      // chess clock: seconds per player, 0 = no clock
+	ClockIncrement uint32      // chess clock: seconds added after each move
 }
 
 // swap2StateBinary stores data for the Gomoku swap opening.
@@ -80,6 +129,27 @@ const (
 	swap2PhaseColorChoice uint8 = 4
 )
 
+// Meta extension tags. Every extension is stored after CreatedAt as
+// tag + 2-byte length + data, so older games without them still load.
+const (
+	metaExtForbiddenLoses uint8 = 1
+	metaExtRuleset        uint8 = 2
+	metaExtMisere         uint8 = 3
+	metaExtPieces         uint8 = 4
+	metaExtTopology       uint8 = 5
+	metaExtBlocked        uint8 = 6
+	metaExtOpening        uint8 = 7
+	metaExtPie            uint8 = 8
+	metaExtAuction        uint8 = 9
+	metaExtCoinFlip       uint8 = 10
+	metaExtInvites        uint8 = 11
+	metaExtJoinHash       uint8 = 12
+	metaExtJoinDeadline   uint8 = 13
+	metaExtMoveTimeout    uint8 = 14
+	metaExtClock          uint8 = 15
+	metaExtJoinedAt       uint8 = 16
+)
+
 // TransferAllow represents an incoming allow-intent for a token.
 // Used to verify joiners supply matching funds before entering the game.
 type TransferAllow struct {
@@ -130,6 +130,20 @@ func parseU64Fast(s string) uint64 {
 	return n
 }
 
+// isDigits reports whether s is a non-empty run of ASCII digits, the only
+// input parseU64Fast handles correctly.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // parseU8Fast extracts a small uint (0-255) from decimal ASCII.
 func parseU8Fast(s string) uint8 {
 	var n uint8
@@ -158,6 +172,36 @@ func appendU64(dst []byte, v uint64) []byte {
 func appendU16(dst []byte, v uint16) []byte { return appendU64(dst, uint64(v)) }
 func appendU8(dst []byte, v uint8) []byte   { return appendU64(dst, uint64(v)) }
 
+// appendKVFields writes flat key/value pairs as "|key=value" fields.
+func appendKVFields(dst []byte, kv []string) []byte {
+	for i := 0; i+1 < len(kv); i += 2 {
+		dst = append(dst, '|')
+		dst = append(dst, kv[i]...)
+		dst = append(dst, '=')
+		dst = append(dst, kv[i+1]...)
+	}
+	return dst
+}
+
+// boolByte maps a flag to 0 or 1 for binary storage.
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// appendU16List prints values as a comma separated decimal list.
+func appendU16List(dst []byte, vs []uint16) []byte {
+	for i, v := range vs {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendU16(dst, v)
+	}
+	return dst
+}
+
 //
 // ---------- Require ----------
 //
@@ -0,0 +1,81 @@
+package contract_test
+
+import (
+	"testing"
+	"vsc-node/modules/db/vsc/contracts"
+)
+
+func TestFirstMoveAuctionAscending(t *testing.T) {
+	ct := SetupContractTest()
+	stake := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.000", "token": "hive"}}}
+	// auction needs a bet > should fail
+	CallContract(t, ct, "g_create", []byte("1|Auction||auction=ascending"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("1|Auction||auction=ascending|auctionwindow=600"), stake, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), stake, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// auction still running > should fail
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_bid", []byte("0"),
+		[]contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "0.100", "token": "hive"}}},
+		"hive:someone", true, uint(1_000_000_000), "", nil)
+	// not higher than the current bid > should fail
+	CallContract(t, ct, "g_bid", []byte("0"),
+		[]contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "0.100", "token": "hive"}}},
+		"hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_bid", []byte("0"),
+		[]contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "0.200", "token": "hive"}}},
+		"hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+
+	later := "2025-09-03T00:20:00"
+	CallContract(t, ct, "g_bid", []byte("0|settle"), nil, "hive:someone", true, uint(1_000_000_000), "", &later)
+	// someoneelse won X and moves first
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", &later)
+}
+
+func TestFirstMoveAuctionSealed(t *testing.T) {
+	ct := SetupContractTest()
+	stake := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.000", "token": "hive"}}}
+	CallContract(t, ct, "g_create", []byte("2|Sealed||auction=sealed|auctionwindow=600"), stake, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), stake, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// sha256("300|salt") and sha256("400|pepper")
+	CallContract(t, ct, "g_bid", []byte("0|7e6b4b72e87e42923a254a08f3bfbee393976edceff53fc94f42d2d71e920c24"), stake, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_bid", []byte("0|e760b8e03f49f8f8890113426d90481c988ca377a794c546858c927303d95010"), stake, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// reveal window not open yet > should fail
+	CallContract(t, ct, "g_bid", []byte("0|reveal|300|salt"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+
+	reveal := "2025-09-03T00:15:00"
+	// wrong salt > should fail
+	CallContract(t, ct, "g_bid", []byte("0|reveal|300|pepper"), nil, "hive:someone", false, uint(1_000_000_000), "", &reveal)
+	CallContract(t, ct, "g_bid", []byte("0|reveal|300|salt"), nil, "hive:someone", true, uint(1_000_000_000), "", &reveal)
+	CallContract(t, ct, "g_bid", []byte("0|reveal|400|pepper"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", &reveal)
+	// both revealed, the next move settles: someoneelse bid more and plays X
+	CallContract(t, ct, "g_move", []byte("0|0|3"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", &reveal)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", &reveal)
+}
+
+func TestFirstMoveAuctionWindowLimit(t *testing.T) {
+	ct := SetupContractTest()
+	stake := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.000", "token": "hive"}}}
+	// longer than a week > should fail
+	CallContract(t, ct, "g_create", []byte("1|Auction||auction=sealed|auctionwindow=604801"), stake, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// the window doesn't depend on the game's own move timeout
+	CallContract(t, ct, "g_create", []byte("1|Auction||auction=sealed|auctionwindow=604800|timeout=300"), stake, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestFirstMoveAuctionSealedRevealDigitsOnly(t *testing.T) {
+	ct := SetupContractTest()
+	stake := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.000", "token": "hive"}}}
+	CallContract(t, ct, "g_create", []byte("2|Sealed||auction=sealed|auctionwindow=600"), stake, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), stake, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// sha256("1e3|salt") and sha256("400|pepper")
+	CallContract(t, ct, "g_bid", []byte("0|dd33ffd71b8ab372feb57e6c53a677cd1a5496b3f0232db0546a16504267be75"), stake, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_bid", []byte("0|e760b8e03f49f8f8890113426d90481c988ca377a794c546858c927303d95010"), stake, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+
+	reveal := "2025-09-03T00:15:00"
+	// matches the commitment but is not a plain amount > should fail
+	CallContract(t, ct, "g_bid", []byte("0|reveal|1e3|salt"), nil, "hive:someone", false, uint(1_000_000_000), "", &reveal)
+	CallContract(t, ct, "g_bid", []byte("0|reveal|400|pepper"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", &reveal)
+	// the unrevealed bid counts as zero: someoneelse plays X
+	later := "2025-09-03T00:25:00"
+	CallContract(t, ct, "g_move", []byte("0|0|3"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", &later)
+}
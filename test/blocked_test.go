@@ -0,0 +1,68 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+func TestBlockedCellBreaksLine(t *testing.T) {
+	ct := SetupContractTest()
+	// off the board > should fail
+	CallContract(t, ct, "g_create", []byte("1|Rocks||blocked=9"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// both layout and seed > should fail
+	CallContract(t, ct, "g_create", []byte("4|Rocks||blocked=1|blockseed=2"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("4|Rocks||blocked=12"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// blocked cell > should fail
+	CallContract(t, ct, "g_move", []byte("0|2|2"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|3"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// four X in row 2, but split by the blocked center > game goes on
+	CallContract(t, ct, "g_move", []byte("0|2|4"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|3"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestBlockedCellsSeededAtJoin(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("2|Random rocks||blockseed=5"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|3"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestBlockedGravityBoardDraws(t *testing.T) {
+	ct := SetupContractTest()
+	// the obstacle in the middle hides the bottom cell of column 1
+	CallContract(t, ct, "g_create", []byte("10|Rock drop||rows=3|cols=3|win=3|gravity=1|blocked=4"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	players := []string{"hive:someone", "hive:someoneelse"}
+	for i, m := range []string{"0|0|0", "0|0|0", "0|0|2", "0|0|2", "0|0|0", "0|0|1", "0|0|2"} {
+		CallContract(t, ct, "g_move", []byte(m), nil, players[i%2], true, uint(1_000_000_000), "", nil)
+	}
+	// no reachable cell left: the game ended in a draw > should fail
+	CallContract(t, ct, "g_move", []byte("0|0|1"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+}
+
+func TestBlockedCellsCheckedAgainstFinalBoard(t *testing.T) {
+	ct := SetupContractTest()
+	// cell 80 lies off the 3x3 board set afterwards > should fail
+	CallContract(t, ct, "g_create", []byte("10|Small||blocked=80|rows=3|cols=3|win=3"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// more random obstacles than a quarter of the 3x3 board > should fail
+	CallContract(t, ct, "g_create", []byte("10|Small||blockseed=10|rows=3|cols=3|win=3"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("10|Small||blocked=8|rows=3|cols=3|win=3"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestBlockedCellsLeaveRoomForTapatanPieces(t *testing.T) {
+	ct := SetupContractTest()
+	// 8 pieces and 2 obstacles don't fit on 9 points > should fail
+	CallContract(t, ct, "g_create", []byte("16|Achi||pieces=4|blocked=0,8"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// same with a random obstacle > should fail
+	CallContract(t, ct, "g_create", []byte("16|Achi||blockseed=1|pieces=4"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("16|Tapatan||blocked=0,8"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
@@ -0,0 +1,80 @@
+package contract_test
+
+import (
+	"testing"
+
+	"vsc-node/modules/db/vsc/contracts"
+)
+
+func TestCancelLobby(t *testing.T) {
+	ct := SetupContractTest()
+	intents := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.000", "token": "hive"}}}
+	CallContract(t, ct, "g_create", []byte("1|Lobby"), intents, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// not the creator > should fail
+	CallContract(t, ct, "g_cancel", []byte("0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_cancel", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// already cancelled > should fail
+	CallContract(t, ct, "g_join", []byte("0"), intents, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_cancel", []byte("0"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+}
+
+func TestAbortBeforeFirstMove(t *testing.T) {
+	ct := SetupContractTest()
+	intents := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.000", "token": "hive"}}}
+	CallContract(t, ct, "g_create", []byte("1|Abort"), intents, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), intents, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// not a player > should fail
+	CallContract(t, ct, "g_cancel", []byte("0"), nil, "hive:anyone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_cancel", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// game is over > should fail
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+
+	CallContract(t, ct, "g_create", []byte("1|Abort"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("1|1|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// first stone already placed > should fail
+	CallContract(t, ct, "g_cancel", []byte("1"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+}
+
+func TestAbortRefusedAfterFirstMovePurchase(t *testing.T) {
+	ct := SetupContractTest()
+	stake := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.000", "token": "hive"}}}
+	withFee := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.500", "token": "hive"}}}
+	CallContract(t, ct, "g_create", []byte("1|Fee|0.500"), stake, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), withFee, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// creator already got the fee > should fail
+	CallContract(t, ct, "g_cancel", []byte("0"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// same for the buyer > should fail
+	CallContract(t, ct, "g_cancel", []byte("0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+}
+
+func TestAbortRefusedAfterAuctionWon(t *testing.T) {
+	ct := SetupContractTest()
+	stake := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.000", "token": "hive"}}}
+	bid := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "0.100", "token": "hive"}}}
+	CallContract(t, ct, "g_create", []byte("1|Auction||auction=ascending|auctionwindow=600"), stake, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), stake, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_bid", []byte("0"), bid, "hive:someone", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:01:00"))
+	// the loser would keep the winning bid > should fail
+	CallContract(t, ct, "g_cancel", []byte("0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:20:00"))
+
+	// while bidding is still open the escrow goes back
+	CallContract(t, ct, "g_create", []byte("1|Auction||auction=ascending|auctionwindow=600"), stake, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("1"), stake, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_bid", []byte("1"), bid, "hive:someone", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:01:00"))
+	CallContract(t, ct, "g_cancel", []byte("1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:02:00"))
+}
+
+func TestAbortRefusedAfterCoinFlipReveal(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("1|Flip||coinflip="+flipCommitCreator), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0|"+flipCommitJoiner), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_reveal", []byte("0|"+flipSecretCreator), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// the joiner could dodge a flip going against them > should fail
+	CallContract(t, ct, "g_cancel", []byte("0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+
+	// nothing revealed yet
+	CallContract(t, ct, "g_create", []byte("1|Flip||coinflip="+flipCommitCreator), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("1|"+flipCommitJoiner), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_cancel", []byte("1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+}
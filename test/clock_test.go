@@ -0,0 +1,24 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+func TestChessClock(t *testing.T) {
+	ct := SetupContractTest()
+	// increment without a clock > should fail
+	CallContract(t, ct, "g_create", []byte("1|Rapid||increment=5"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// clock and timeout together > should fail
+	CallContract(t, ct, "g_create", []byte("1|Rapid||clock=600|timeout=600"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("1|Rapid||clock=600|increment=10"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T01:00:00"))
+	// X uses 100s → 510 left, O uses 300s → 310 left
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someone", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T01:01:40"))
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T01:06:40"))
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// X still has time > should fail
+	CallContract(t, ct, "g_timeout", []byte("0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", toStringPtr("2025-09-03T01:15:10"))
+	// bank ran out > should fail
+	CallContract(t, ct, "g_move", []byte("0|2|2"), nil, "hive:someone", false, uint(1_000_000_000), "", toStringPtr("2025-09-03T01:15:11"))
+	CallContract(t, ct, "g_timeout", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T01:15:11"))
+}
@@ -0,0 +1,42 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+// secrets: 00…01 (creator) and 00…00 (joiner), odd xor gives X to the joiner
+const (
+	flipCommitCreator = "ec4916dd28fc4c10d78e287ca5d9cc51ee1ae73cbfde08c6b37324cbfaac8bc5"
+	flipCommitJoiner  = "66687aadf862bd776c8fc18b8e9f8e20089714856ee233b3902a591d0d5f2925"
+	flipSecretCreator = "0000000000000000000000000000000000000000000000000000000000000001"
+	flipSecretJoiner  = "0000000000000000000000000000000000000000000000000000000000000000"
+)
+
+func TestCoinFlipDecidesSwap2Seats(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("3|Flip||coinflip="+flipCommitCreator), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// commitment missing > should fail
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0|"+flipCommitJoiner), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// flip still open > should fail
+	CallContract(t, ct, "g_swap", []byte("0|place|7-7-1"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// not the committed secret > should fail
+	CallContract(t, ct, "g_reveal", []byte("0|"+flipSecretJoiner), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_reveal", []byte("0|"+flipSecretCreator), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_reveal", []byte("0|"+flipSecretJoiner), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// joiner got X and opens
+	CallContract(t, ct, "g_swap", []byte("0|place|7-7-1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestCoinFlipForfeit(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("1|Flip||coinflip="+flipCommitCreator), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0|"+flipCommitJoiner), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_reveal", []byte("0|"+flipSecretCreator), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// deadline not reached > should fail
+	CallContract(t, ct, "g_timeout", []byte("0"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	later := "2025-09-05T00:00:00"
+	CallContract(t, ct, "g_timeout", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", &later)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
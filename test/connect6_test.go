@@ -0,0 +1,31 @@
+package contract_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnect6CreatorWin(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("9|Connect6|"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// opening is a single stone
+	CallContract(t, ct, "g_move", []byte("0|9|9|9|10"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|9|9"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// afterwards two stones per turn > should fail
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	// the rejected stone was not stored
+	fields := strings.Split(getGame(t, ct, "0"), "|")
+	assert.Equal(t, "1", fields[getFieldMoves])
+	assert.Equal(t, "1", fields[getFieldStatus])
+	CallContract(t, ct, "g_move", []byte("0|0|0|0|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|9|10|9|11"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|0|1|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|9|12|9|13"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|0|2|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// 9|8 completes six in a row
+	CallContract(t, ct, "g_move", []byte("0|9|8|5|5"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	assertGameResult(t, ct, "0", "2", "hive:someone")
+}
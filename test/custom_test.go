@@ -0,0 +1,84 @@
+package contract_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomBoardGravityWin(t *testing.T) {
+	ct := SetupContractTest()
+	// missing ruleset > should fail
+	CallContract(t, ct, "g_create", []byte("10|Custom|"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// rules only for custom games > should fail
+	CallContract(t, ct, "g_create", []byte("1|XOXO||rows=7"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// trailing field that is not key=value > should fail
+	CallContract(t, ct, "g_create", []byte("1|XOXO||extra"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("10|Drop four on 7x8||rows=7|cols=8|win=4|gravity=1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// four stacked in column 0 win on a win=4 board
+	assertGameResult(t, ct, "0", "2", "hive:someone")
+}
+
+func TestCustomBoardLoseOnThree(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("10|Five not three||rows=9|cols=9|win=5|lose=3"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|5|5"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|6|6"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// creator makes 3 in a row and loses
+	CallContract(t, ct, "g_move", []byte("0|0|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	assertGameResult(t, ct, "0", "2", "hive:someoneelse")
+}
+
+func TestCustomBoardSixToWin(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("10|Six on 9x9||rows=9|cols=9|win=6"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	for i := 0; i < 5; i++ {
+		CallContract(t, ct, "g_move", []byte("0|4|"+strconv.Itoa(i)), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+		CallContract(t, ct, "g_move", []byte("0|8|"+strconv.Itoa(2*i)), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	}
+	// five in a row is not enough on this board
+	assertGameResult(t, ct, "0", "1", "")
+	CallContract(t, ct, "g_move", []byte("0|4|5"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	assertGameResult(t, ct, "0", "2", "hive:someone")
+}
+
+func TestCustomBoardWideCells(t *testing.T) {
+	ct := SetupContractTest()
+	// 20x20 has more than 256 cells > moves use the v2 encoding
+	CallContract(t, ct, "g_create", []byte("10|Big board||rows=20|cols=20|win=5"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	_, _, logs := CallContract(t, ct, "g_move", []byte("0|19|18"), nil, "hive:someone", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:05:00"))
+	assert.Equal(t, "m|id=0|by=hive:someone|cell=398|ts=1756857900|v=2", findEvent(logs, "m|"))
+	_, _, logs = CallContract(t, ct, "g_move", []byte("0|19|19"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:07:00"))
+	assert.Equal(t, "m|id=0|by=hive:someoneelse|cell=399|ts=1756858020|v=2", findEvent(logs, "m|"))
+	// both stored moves read back: cells on the board, time of the last one
+	fields := strings.Split(getGame(t, ct, "0"), "|")
+	assert.Equal(t, "1756858020", fields[getFieldLastMoveAt])
+	assert.Equal(t, strings.Repeat("0", 398)+"12", fields[getFieldBoard])
+}
+
+func TestSmallBoardMovesStayV1(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("1|XOXO|"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// no v=2 marker for boards up to 256 cells
+	_, _, logs := CallContract(t, ct, "g_move", []byte("0|2|1"), nil, "hive:someone", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:05:00"))
+	assert.Equal(t, "m|id=0|by=hive:someone|cell=7|ts=1756857900", findEvent(logs, "m|"))
+	fields := strings.Split(getGame(t, ct, "0"), "|")
+	assert.Equal(t, "1756857900", fields[getFieldLastMoveAt])
+	assert.Equal(t, "000000010", fields[getFieldBoard])
+}
@@ -0,0 +1,42 @@
+package contract_test
+
+import (
+	"testing"
+
+	"vsc-node/modules/db/vsc/contracts"
+)
+
+func TestDrawByAgreement(t *testing.T) {
+	ct := SetupContractTest()
+	intents := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.000", "token": "hive"}}}
+	CallContract(t, ct, "g_create", []byte("3|Gomoku"), intents, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// nobody joined yet > should fail
+	CallContract(t, ct, "g_offer_draw", []byte("0"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), intents, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_offer_draw", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// own offer > should fail
+	CallContract(t, ct, "g_accept_draw", []byte("0"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_decline_draw", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// offer already declined > should fail
+	CallContract(t, ct, "g_accept_draw", []byte("0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+
+	CallContract(t, ct, "g_offer_draw", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_accept_draw", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// game is over > should fail
+	CallContract(t, ct, "g_offer_draw", []byte("0"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+}
+
+func TestDrawOfferLapsesOnMove(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("1|XOXO"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_offer_draw", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// moving lapsed the offer > should fail
+	CallContract(t, ct, "g_accept_draw", []byte("0"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// the offerer's own move keeps it
+	CallContract(t, ct, "g_offer_draw", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_accept_draw", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
@@ -0,0 +1,34 @@
+package contract_test
+
+import (
+	"testing"
+
+	"vsc-node/modules/db/vsc/contracts"
+)
+
+func TestLobbyExpiry(t *testing.T) {
+	ct := SetupContractTest()
+	intents := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.000", "token": "hive"}}}
+	// below the minimum deadline > should fail
+	CallContract(t, ct, "g_create", []byte("1|Quick||expiry=10"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("1|Quick||expiry=3600"), intents, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// deadline not reached > should fail
+	CallContract(t, ct, "g_expire", []byte("0"), nil, "hive:anyone", false, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:30:00"))
+
+	later := "2025-09-03T01:00:01"
+	// lobby expired > should fail
+	CallContract(t, ct, "g_join", []byte("0"), intents, "hive:someoneelse", false, uint(1_000_000_000), "", &later)
+	CallContract(t, ct, "g_expire", []byte("0"), nil, "hive:anyone", true, uint(1_000_000_000), "", &later)
+	// already cancelled > should fail
+	CallContract(t, ct, "g_expire", []byte("0"), nil, "hive:anyone", false, uint(1_000_000_000), "", &later)
+	CallContract(t, ct, "g_resign", []byte("0"), nil, "hive:someone", false, uint(1_000_000_000), "", &later)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:anyone", true, uint(1_000_000_000), "", &later)
+}
+
+func TestLobbyExpiryAfterJoin(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("1|Quick||expiry=3600"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:10:00"))
+	// game already running > should fail
+	CallContract(t, ct, "g_expire", []byte("0"), nil, "hive:anyone", false, uint(1_000_000_000), "", toStringPtr("2025-09-04T00:00:00"))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"vsc-node/lib/test_utils"
@@ -178,3 +179,41 @@ func RunContractTests(t *testing.T, ct *test_utils.ContractTest, tests []Contrac
 func toStringPtr(s string) *string {
 	return &s
 }
+
+// Positions of the g_get fields checked by the tests
+// (id|type|name|creator|opponent|rows|cols|turn|moves|status|winner|...).
+const (
+	getFieldMoves      = 8
+	getFieldStatus     = 9
+	getFieldWinner     = 10
+	getFieldLastMoveAt = 13
+	getFieldBoard      = 16
+)
+
+// getGame returns the g_get output of a game.
+func getGame(t *testing.T, ct *test_utils.ContractTest, id string) string {
+	result, _, _ := CallContract(t, ct, "g_get", []byte(id), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	return result.Ret
+}
+
+// assertGameResult checks status and winner ("" = none) of a game via g_get.
+func assertGameResult(t *testing.T, ct *test_utils.ContractTest, id string, status string, winner string) {
+	fields := strings.Split(getGame(t, ct, id), "|")
+	if assert.Greater(t, len(fields), getFieldWinner, "short g_get output") {
+		assert.Equal(t, status, fields[getFieldStatus], "status")
+		assert.Equal(t, winner, fields[getFieldWinner], "winner")
+	}
+}
+
+// findEvent returns the last logged event starting with prefix, or "".
+func findEvent(logs map[string][]string, prefix string) string {
+	found := ""
+	for _, values := range logs {
+		for _, v := range values {
+			if strings.HasPrefix(v, prefix) {
+				found = v
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,29 @@
+package contract_test
+
+import (
+	"testing"
+	"vsc-node/modules/db/vsc/contracts"
+)
+
+func TestInviteOnlyJoin(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("1|Friends||invite=hive:someoneelse"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// not invited > should fail
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:stranger", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestInviteDeclineRefunds(t *testing.T) {
+	ct := SetupContractTest()
+	stake := []contracts.Intent{{Type: "transfer.allow", Args: map[string]string{"limit": "1.000", "token": "hive"}}}
+	CallContract(t, ct, "g_create", []byte("1|Friends||invite=hive:someoneelse,hive:friend"), stake, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// not invited > should fail
+	CallContract(t, ct, "g_decline", []byte("0"), nil, "hive:stranger", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_decline", []byte("0"), nil, "hive:friend", true, uint(1_000_000_000), "", nil)
+	// last invitee declines, stake goes back to the creator
+	CallContract(t, ct, "g_decline", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// lobby closed > should fail
+	CallContract(t, ct, "g_join", []byte("0"), stake, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
@@ -0,0 +1,38 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+func TestMisereTicTacToeLineLoses(t *testing.T) {
+	ct := SetupContractTest()
+	// misère is not available for gomoku > should fail
+	CallContract(t, ct, "g_create", []byte("3|Gomoku||misere=1"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("1|Misere||misere=1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// X completes the top row > X loses
+	CallContract(t, ct, "g_move", []byte("0|0|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestNotaktoLastBoardLoses(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("15|Notakto||boards=6"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("15|Notakto||boards=2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// first board dies, second one is still alive > game goes on
+	CallContract(t, ct, "g_move", []byte("0|0|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// dead board takes no more stones > should fail
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|3|0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|3|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// O kills the last board > X wins
+	CallContract(t, ct, "g_move", []byte("0|3|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
@@ -0,0 +1,57 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+func TestOpeningSwap1(t *testing.T) {
+	ct := SetupContractTest()
+	// renju stays on swap2 > should fail
+	CallContract(t, ct, "g_create", []byte("7|Renju||opening=swap1"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("3|Swap1||opening=swap1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// opening still running > should fail
+	CallContract(t, ct, "g_move", []byte("0|7|7"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|place|7-7|7-8|8-8"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|choose|swap"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// seats swapped, someone now plays O and moves 4th
+	CallContract(t, ct, "g_move", []byte("0|6|6"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestOpeningYamaguchi(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("6|Yamaguchi||opening=yamaguchi"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// third stone outside the 5x5 center > should fail
+	CallContract(t, ct, "g_swap", []byte("0|place|7-7|7-8|2-2"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|place|7-7|7-8|9-9"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|declare|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|choose|stay"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|place|6-8"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// fewer offers than declared > should fail
+	CallContract(t, ct, "g_swap", []byte("0|offer|5-5"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|offer|5-5|9-7"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// not offered > should fail
+	CallContract(t, ct, "g_swap", []byte("0|pick|0-0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|pick|9-7"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|6|6"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+}
+
+func TestOpeningTaraguchiOffers(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("3|Taraguchi||opening=taraguchi10"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|place|7-7"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|choose|stay"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|place|6-7"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|choose|stay"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|place|5-5"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|choose|stay"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|place|4-4"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|choose|stay"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|offer|0-0|0-1|0-2|0-3|0-4|0-5|0-6|0-7|0-8|0-9"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|pick|0-4"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
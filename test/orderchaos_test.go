@@ -0,0 +1,54 @@
+package contract_test
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestOrderChaosOrderWins(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("14|OrderChaos|"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// missing symbol > should fail
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|1|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|2|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|3|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// five O in a row, no matter who placed them > Order (X seat) wins
+	CallContract(t, ct, "g_move", []byte("0|0|4|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestOrderChaosFullBoardChaosWins(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("14|OrderChaos|"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	players := []string{"hive:someone", "hive:someoneelse"}
+	n := 0
+	for r := 0; r < 6; r++ {
+		for c := 0; c < 6; c++ {
+			// pairs of symbols shifting every row, so no five of a kind appear
+			sym := 1 + (c/2+r+r/3)%2
+			CallContract(t, ct, "g_move", []byte(fmt.Sprintf("0|%d|%d|%d", r, c, sym)), nil, players[n%2], true, uint(1_000_000_000), "", nil)
+			n++
+		}
+	}
+	// board is full without a line > Chaos (O seat) wins
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestOrderChaosOverlineDoesNotWin(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("14|OrderChaos|"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|1|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|2|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|3|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|5|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// six O in a row is an overline, not a win for Order
+	CallContract(t, ct, "g_move", []byte("0|0|4|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// game goes on
+	CallContract(t, ct, "g_move", []byte("0|1|0|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
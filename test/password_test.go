@@ -0,0 +1,29 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+// sha256("open sesame")
+const lobbyPasswordHash = "41ef4bb0b23661e66301aac36066912dac037827b4ae63a7b1165a5aa93ed4eb"
+
+func TestPasswordLockedLobby(t *testing.T) {
+	ct := SetupContractTest()
+	// not a sha256 hex digest > should fail
+	CallContract(t, ct, "g_create", []byte("1|Locked||password=secret"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("1|Locked||password="+lobbyPasswordHash), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// password missing > should fail
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	// wrong password > should fail
+	CallContract(t, ct, "g_join", []byte("0|open"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0|open sesame"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestPasswordWithSeparatorCannotJoin(t *testing.T) {
+	ct := SetupContractTest()
+	// sha256("open|sesame")
+	CallContract(t, ct, "g_create", []byte("1|Locked||password=176307f945f201a8f97a1877656001176cc62630559b66e4b1ae1ef49b041809"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// '|' is never part of a password > should fail
+	CallContract(t, ct, "g_join", []byte("0|open|sesame"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+}
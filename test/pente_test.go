@@ -0,0 +1,65 @@
+package contract_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPenteCapturePair(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("8|Pente|"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|9|9"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|9|10"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|9|11"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// X flanks the O pair and captures it
+	_, _, logs := CallContract(t, ct, "g_move", []byte("0|9|12"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	assert.Contains(t, findEvent(logs, "m|"), "|cap=182,181")
+	assert.Contains(t, getGame(t, ct, "0"), "|cx=1|co=0|cap=182,181")
+	// captured cell is free again
+	CallContract(t, ct, "g_move", []byte("0|9|10"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+}
+
+func TestPenteCaptureOnEdge(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("8|Pente|"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|18"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|18"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|10|10"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|18"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// X captures down the last column
+	_, _, logs := CallContract(t, ct, "g_move", []byte("0|3|18"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	assert.Contains(t, findEvent(logs, "m|"), "|cap=56,37")
+	// O pair on 5,18 and 6,0: consecutive cells, but not on one line
+	CallContract(t, ct, "g_move", []byte("0|5|18"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|5|17"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|6|0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	_, _, logs = CallContract(t, ct, "g_move", []byte("0|6|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	assert.NotContains(t, findEvent(logs, "m|"), "cap=")
+	assert.Contains(t, getGame(t, ct, "0"), "|cx=1|co=0|cap=56,37")
+}
+
+func TestPenteWinByFivePairs(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("8|Pente|"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// every third row: O plays a pair on columns 1-2, X flanks it from column 0 and 3
+	for i := 0; i < 5; i++ {
+		r := strconv.Itoa(i * 3)
+		spare := strconv.Itoa(i*3 + 1)
+		CallContract(t, ct, "g_move", []byte("0|"+r+"|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+		CallContract(t, ct, "g_move", []byte("0|"+r+"|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+		CallContract(t, ct, "g_move", []byte("0|"+spare+"|18"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+		CallContract(t, ct, "g_move", []byte("0|"+r+"|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+		CallContract(t, ct, "g_move", []byte("0|"+r+"|3"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+		if i < 4 {
+			CallContract(t, ct, "g_move", []byte("0|"+spare+"|10"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+		}
+	}
+	assertGameResult(t, ct, "0", "2", "hive:someone")
+	assert.Contains(t, getGame(t, ct, "0"), "|cx=5|co=0|")
+}
@@ -0,0 +1,23 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+func TestPieRuleSwap(t *testing.T) {
+	ct := SetupContractTest()
+	// tic tac toe has no pie rule > should fail
+	CallContract(t, ct, "g_create", []byte("1|Pie||pie=1"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("2|Pie||pie=1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// no first move yet > should fail
+	CallContract(t, ct, "g_swap", []byte("0|choose|swap"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|3"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// only O may swap > should fail
+	CallContract(t, ct, "g_swap", []byte("0|choose|swap"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|choose|swap"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// no swapping back > should fail
+	CallContract(t, ct, "g_swap", []byte("0|choose|swap"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
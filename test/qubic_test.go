@@ -0,0 +1,29 @@
+package contract_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQubicSpaceDiagonal(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("12|Qubic|"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// layer out of range > should fail
+	CallContract(t, ct, "g_move", []byte("0|4|0|0"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// missing layer > should fail
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|1|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|2|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0|3"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// X completes the space diagonal through all four layers > X wins
+	_, _, logs := CallContract(t, ct, "g_move", []byte("0|3|3|3"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	assert.Contains(t, findEvent(logs, "w|"), "|winner=hive:someone|")
+	// game is over > should fail
+	CallContract(t, ct, "g_move", []byte("0|3|0|0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	assertGameResult(t, ct, "0", "2", "hive:someone")
+}
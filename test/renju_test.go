@@ -0,0 +1,69 @@
+package contract_test
+
+import (
+	"testing"
+
+	"vsc-node/lib/test_utils"
+)
+
+// renjuShape is a position built by alternating O and X moves, and the X
+// move that completes it.
+type renjuShape struct {
+	name string
+	o, x []string
+	last string
+}
+
+var renjuForbiddenShapes = []renjuShape{
+	// open threes on row 7 and column 7
+	{"double three", []string{"10|0", "10|2", "10|4", "10|6"}, []string{"7|5", "7|6", "5|7", "6|7"}, "7|7"},
+	// fours on row 7 and column 7, each closed at one end by O
+	{"double four", []string{"7|3", "3|7", "12|0", "12|2", "12|4", "12|6"}, []string{"7|4", "7|5", "7|6", "4|7", "5|7", "6|7"}, "7|7"},
+	// six in a row on row 3
+	{"overline", []string{"12|0", "12|2", "12|4", "12|6", "12|8"}, []string{"3|2", "3|3", "3|4", "3|5", "3|7"}, "3|6"},
+}
+
+// setupRenju creates a Renju game with the given options, runs the swap2
+// opening in the corners (someone keeps X, black) and plays the O and X
+// moves in turn. X is to move when it returns.
+func setupRenju(t *testing.T, opts string, o, x []string) *test_utils.ContractTest {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("7|Renju|"+opts), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|place|0-0-1|14-14-2|0-14-1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_swap", []byte("0|choose|stay"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	for i := range o {
+		CallContract(t, ct, "g_move", []byte("0|"+o[i]), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+		CallContract(t, ct, "g_move", []byte("0|"+x[i]), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	}
+	CallContract(t, ct, "g_move", []byte("0|12|14"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	return ct
+}
+
+func TestRenjuForbiddenRejected(t *testing.T) {
+	for _, s := range renjuForbiddenShapes {
+		t.Run(s.name, func(t *testing.T) {
+			ct := setupRenju(t, "", s.o, s.x)
+			// forbidden for X > should fail
+			CallContract(t, ct, "g_move", []byte("0|"+s.last), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+			assertGameResult(t, ct, "0", "1", "")
+		})
+	}
+}
+
+func TestRenjuForbiddenLoses(t *testing.T) {
+	for _, s := range renjuForbiddenShapes {
+		t.Run(s.name, func(t *testing.T) {
+			ct := setupRenju(t, "|fb=lose", s.o, s.x)
+			// accepted, but X loses on the spot
+			CallContract(t, ct, "g_move", []byte("0|"+s.last), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+			assertGameResult(t, ct, "0", "2", "hive:someoneelse")
+		})
+	}
+}
+
+func TestRenjuExactFiveWins(t *testing.T) {
+	ct := setupRenju(t, "", []string{"12|0", "12|2", "12|4", "12|6"}, []string{"7|3", "7|4", "7|5", "7|6"})
+	CallContract(t, ct, "g_move", []byte("0|7|7"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	assertGameResult(t, ct, "0", "2", "hive:someone")
+}
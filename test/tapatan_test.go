@@ -0,0 +1,31 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+func TestTapatanMovementPhase(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("16|Tapatan||pieces=5"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("16|Tapatan|"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|1"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// all pieces placed > placing another one should fail
+	CallContract(t, ct, "g_move", []byte("0|0|2"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// not an adjacent point > should fail
+	CallContract(t, ct, "g_move", []byte("0|2|2|2|0"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// moving the opponent's piece > should fail
+	CallContract(t, ct, "g_move", []byte("0|1|0|0|2"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	// no diagonal line through an edge point > should fail
+	CallContract(t, ct, "g_move", []byte("0|0|1|1|2"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|2|1|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|1|2|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// X slides into the top row > X wins
+	CallContract(t, ct, "g_move", []byte("0|1|2|0|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
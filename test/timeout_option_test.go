@@ -0,0 +1,38 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+func TestMoveTimeoutOption(t *testing.T) {
+	ct := SetupContractTest()
+	// below one minute > should fail
+	CallContract(t, ct, "g_create", []byte("1|Blitz||timeout=5"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("1|Blitz||timeout=600"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someone", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:01:00"))
+	// ten minutes not yet over > should fail
+	CallContract(t, ct, "g_timeout", []byte("0"), nil, "hive:someone", false, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:11:00"))
+	CallContract(t, ct, "g_timeout", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T00:11:01"))
+}
+
+func TestCorrespondenceTimeout(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("1|Slow||timeout=1209600"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// past 7 days but within 14 > should fail
+	CallContract(t, ct, "g_timeout", []byte("0"), nil, "hive:someone", false, uint(1_000_000_000), "", toStringPtr("2025-09-11T00:00:00"))
+	CallContract(t, ct, "g_timeout", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", toStringPtr("2025-09-17T00:00:01"))
+}
+
+func TestFirstMoveTimeoutCountsFromJoin(t *testing.T) {
+	ct := SetupContractTest()
+	CallContract(t, ct, "g_create", []byte("1|Blitz||timeout=600"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T01:00:00"))
+	// right after the join > should fail
+	CallContract(t, ct, "g_timeout", []byte("0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", toStringPtr("2025-09-03T01:00:01"))
+	// ten minutes since creation but not since the join > should fail
+	CallContract(t, ct, "g_timeout", []byte("0"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", toStringPtr("2025-09-03T01:10:00"))
+	CallContract(t, ct, "g_timeout", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", toStringPtr("2025-09-03T01:10:01"))
+}
@@ -0,0 +1,37 @@
+package contract_test
+
+import (
+	"testing"
+)
+
+func TestTorusTicTacToe5WrapsRow(t *testing.T) {
+	ct := SetupContractTest()
+	// sub-board geometry can't wrap > should fail
+	CallContract(t, ct, "g_create", []byte("11|Ultimate||wrap=torus"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("4|Torus||wrap=torus"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|3"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|0|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|4"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|2|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|3|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// columns 3,4,0,1 of row 2 form a line across the edge > X wins
+	CallContract(t, ct, "g_move", []byte("0|2|1"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}
+
+func TestFallLeftTicTacToe(t *testing.T) {
+	ct := SetupContractTest()
+	// swap2 stones are placed freely > should fail
+	CallContract(t, ct, "g_create", []byte("3|Gomoku||fall=left"), nil, "hive:someone", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_create", []byte("1|Sideways||fall=left"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_join", []byte("0"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	// pieces slide to the left end of their row
+	CallContract(t, ct, "g_move", []byte("0|1|2"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|2"), nil, "hive:someoneelse", true, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_move", []byte("0|1|0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+	// row is full > should fail
+	CallContract(t, ct, "g_move", []byte("0|1|1"), nil, "hive:someoneelse", false, uint(1_000_000_000), "", nil)
+	CallContract(t, ct, "g_get", []byte("0"), nil, "hive:someone", true, uint(1_000_000_000), "", nil)
+}